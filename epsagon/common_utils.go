@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
-	"log"
 	"net/http"
 	"reflect"
 	"strings"
@@ -70,6 +69,25 @@ func NewTracerConfig(applicationName, token string) *Config {
 	}
 }
 
+// RegisterConfig registers a named tracer configuration, for processes that
+// trace several applications. See NamedConfig
+func RegisterConfig(name string, config *Config) {
+	if config == nil {
+		config = &Config{}
+	}
+	tracer.RegisterConfig(name, &config.Config)
+}
+
+// NamedConfig creates a Config that targets a registered configuration.
+// Options set on it override the registered ones for the wrappers it is passed to
+func NamedConfig(name string) *Config {
+	return &Config{
+		Config: tracer.Config{
+			ConfigName: name,
+		},
+	}
+}
+
 // Label adds a label to the sent trace
 func Label(key string, value interface{}, args ...context.Context) {
 	currentTracer := ExtractTracer(args)
@@ -94,6 +112,29 @@ func TypeError(value interface{}, errorType string, args ...context.Context) {
 	}
 }
 
+// Metric adds a custom metric observation of the given kind to the sent trace
+func Metric(kind tracer.MetricKind, name string, value float64, unit string, args ...context.Context) {
+	currentTracer := ExtractTracer(args)
+	if currentTracer != nil {
+		tracer.AddTracerMetric(currentTracer, kind, name, value, unit)
+	}
+}
+
+// Counter adds value to a counter metric of the sent trace
+func Counter(name string, value float64, unit string, args ...context.Context) {
+	Metric(tracer.CounterMetric, name, value, unit, args...)
+}
+
+// Gauge sets the value of a gauge metric of the sent trace
+func Gauge(name string, value float64, unit string, args ...context.Context) {
+	Metric(tracer.GaugeMetric, name, value, unit, args...)
+}
+
+// Histogram adds an observation to a histogram metric of the sent trace
+func Histogram(name string, value float64, unit string, args ...context.Context) {
+	Metric(tracer.HistogramMetric, name, value, unit, args...)
+}
+
 // FormatHeaders format HTTP headers to string - using first header value, ignoring the rest
 func FormatHeaders(headers http.Header) (string, error) {
 	headersToFormat := make(map[string]string)
@@ -167,7 +208,7 @@ func NewReadCloser(body []byte, err error) io.ReadCloser {
 
 func DebugLog(debugMode bool, args ...interface{}) {
 	if debugMode {
-		log.Println("[EPSAGON]", args)
+		tracer.DefaultLogger(true).Debugf("%s", fmt.Sprintln(args...))
 	}
 }
 
@@ -60,6 +60,15 @@ func getRunnerLabels(runner *protocol.Event) map[string]interface{} {
 	return labelsMap
 }
 
+func getRunnerMetrics(runner *protocol.Event) map[string]map[string]interface{} {
+	metrics, ok := runner.Resource.Metadata[tracer.MetricsKey]
+	Expect(ok).To(BeTrue())
+	var metricsMap map[string]map[string]interface{}
+	err := json.Unmarshal([]byte(metrics), &metricsMap)
+	Expect(err).To(BeNil())
+	return metricsMap
+}
+
 func verifyException(errorType string, errorMessage string, exception *protocol.Exception) {
 	Expect(errorType).To(Equal(exception.Type))
 	Expect(errorMessage).To(Equal(exception.Message))
@@ -227,6 +236,83 @@ var _ = Describe("Custom trace fields", func() {
 				Expect(len(labelsMap)).To(Equal(1))
 				verifyLabelValue(TestLabelKey, value, labelsMap)
 			})
+			It("Test custom counter metric", func() {
+				resourceName := "test-resource-name"
+				epsagon.GoWrapper(
+					config,
+					func() {
+						epsagon.Counter("items", 2, "")
+						epsagon.Counter("items", 3, "")
+					},
+					resourceName,
+				)()
+				runnerEvent := waitForTrace(traceChannel, resourceName)
+				metricsMap := getRunnerMetrics(runnerEvent)
+				Expect(len(metricsMap)).To(Equal(1))
+				Expect(metricsMap["items"]["type"]).To(Equal("counter"))
+				Expect(metricsMap["items"]["count"]).To(BeNumerically("==", 2))
+				Expect(metricsMap["items"]["value"]).To(BeNumerically("==", 5))
+			})
+			It("Test custom gauge and histogram metrics", func() {
+				resourceName := "test-resource-name"
+				epsagon.GoWrapper(
+					config,
+					func() {
+						epsagon.Gauge("queue_size", 7, "messages")
+						epsagon.Gauge("queue_size", 4, "messages")
+						epsagon.Histogram("latency", 10, "ms")
+						epsagon.Histogram("latency", 30, "ms")
+						epsagon.Histogram("latency", 20, "ms")
+					},
+					resourceName,
+				)()
+				runnerEvent := waitForTrace(traceChannel, resourceName)
+				metricsMap := getRunnerMetrics(runnerEvent)
+				Expect(len(metricsMap)).To(Equal(2))
+				Expect(metricsMap["queue_size"]["value"]).To(BeNumerically("==", 4))
+				Expect(metricsMap["queue_size"]["unit"]).To(Equal("messages"))
+				Expect(metricsMap["latency"]["type"]).To(Equal("histogram"))
+				Expect(metricsMap["latency"]["count"]).To(BeNumerically("==", 3))
+				Expect(metricsMap["latency"]["value"]).To(BeNumerically("==", 60))
+				Expect(metricsMap["latency"]["min"]).To(BeNumerically("==", 10))
+				Expect(metricsMap["latency"]["max"]).To(BeNumerically("==", 30))
+			})
+			It("Test metric reported with a different kind is ignored", func() {
+				resourceName := "test-resource-name"
+				epsagon.GoWrapper(
+					config,
+					func() {
+						epsagon.Counter("items", 2, "")
+						epsagon.Gauge("items", 10, "")
+					},
+					resourceName,
+				)()
+				runnerEvent := waitForTrace(traceChannel, resourceName)
+				metricsMap := getRunnerMetrics(runnerEvent)
+				Expect(metricsMap["items"]["type"]).To(Equal("counter"))
+				Expect(metricsMap["items"]["value"]).To(BeNumerically("==", 2))
+			})
+			It("Test captured logs and trace ID", func() {
+				resourceName := "test-resource-name"
+				epsagon.GoWrapper(
+					config,
+					func() {
+						currentTracer := epsagon.TracerFromContext(nil)
+						Expect(currentTracer).NotTo(BeNil())
+						tracer.AddTracerLog(currentTracer, tracer.NewLogRecord(time.Now(), "info", "first line"))
+						tracer.AddTracerLog(currentTracer, tracer.NewLogRecord(time.Now(), "error", "second line"))
+					},
+					resourceName,
+				)()
+				runnerEvent := waitForTrace(traceChannel, resourceName)
+				Expect(runnerEvent.Resource.Metadata[tracer.TraceIDKey]).NotTo(BeEmpty())
+				var logs []tracer.LogRecord
+				err := json.Unmarshal([]byte(runnerEvent.Resource.Metadata[tracer.LogsKey]), &logs)
+				Expect(err).To(BeNil())
+				Expect(len(logs)).To(Equal(2))
+				Expect(logs[0].Message).To(Equal("first line"))
+				Expect(logs[1].Level).To(Equal("error"))
+			})
 			It("Default custom error - string error message", func() {
 				resourceName := "test-resource-name"
 				errorMessage := "test_value"
@@ -297,6 +383,14 @@ var _ = Describe("Custom trace fields", func() {
 				}()
 				Expect(output).To(Equal(value))
 			})
+			It("Custom metric - no tracer", func() {
+				value := 3
+				output := func() int {
+					epsagon.Counter("a", 3, "")
+					return value
+				}()
+				Expect(output).To(Equal(value))
+			})
 			It("Custom error - no tracer", func() {
 				errorMessage := "test_value"
 				value := 3
@@ -4,11 +4,10 @@ import (
 	"fmt"
 	"reflect"
 	"runtime"
-	"runtime/debug"
+	"sync"
 
 	"github.com/epsagon/epsagon-go/protocol"
 	"github.com/epsagon/epsagon-go/tracer"
-	"github.com/google/uuid"
 )
 
 type userError struct {
@@ -22,6 +21,9 @@ type GenericWrapper struct {
 	config        *Config
 	tracer        tracer.Tracer
 	runner        *protocol.Event
+	runnerLock    sync.Mutex
+	runnerAdded   bool
+	runtimeStats  *tracer.RuntimeStats
 	thrownError   interface{}
 	resourceName  string
 	invoked       bool
@@ -55,9 +57,9 @@ func (wrapper *GenericWrapper) createRunner() {
 		resourceName = runtime.FuncForPC(wrapper.handler.Pointer()).Name()
 	}
 	wrapper.runner = &protocol.Event{
-		Id:        uuid.New().String(),
+		Id:        tracer.NewTracerID(wrapper.tracer),
 		Origin:    "runner",
-		StartTime: tracer.GetTimestamp(),
+		StartTime: tracer.GetTracerTimestamp(wrapper.tracer),
 		Resource: &protocol.Resource{
 			Name:      resourceName,
 			Type:      "go-function",
@@ -66,6 +68,23 @@ func (wrapper *GenericWrapper) createRunner() {
 		},
 		ErrorCode: protocol.ErrorCode_OK,
 	}
+	if config := wrapper.tracerConfig(); config != nil && config.RuntimeMetrics {
+		wrapper.runtimeStats = tracer.CaptureRuntimeStats()
+	}
+}
+
+// tracerConfig returns the config of the wrapper tracer, which includes the
+// overrides applied when the tracer was created
+func (wrapper *GenericWrapper) tracerConfig() *tracer.Config {
+	if wrapper.tracer != nil {
+		if config := wrapper.tracer.GetConfig(); config != nil {
+			return config
+		}
+	}
+	if wrapper.config == nil {
+		return nil
+	}
+	return &wrapper.config.Config
 }
 
 // For instances when you want to add event but can't risk exception
@@ -76,12 +95,39 @@ func (wrapper *GenericWrapper) safeAddRunnerEvent() {
 	wrapper.addRunnerEvent()
 }
 
+// safeAddRunnerException adds the runner event with the exception of the
+// function, unless Shutdown already added it as interrupted
+func (wrapper *GenericWrapper) safeAddRunnerException(exception *protocol.Exception) {
+	defer func() {
+		recover()
+	}()
+	wrapper.runnerLock.Lock()
+	defer wrapper.runnerLock.Unlock()
+	if wrapper.runnerAdded {
+		return
+	}
+	wrapper.runner.Exception = exception
+	wrapper.runner.ErrorCode = protocol.ErrorCode_EXCEPTION
+	wrapper.addRunnerEventLocked()
+}
+
 func (wrapper *GenericWrapper) addRunnerEvent() {
-	if wrapper.dontAddRunner {
+	wrapper.runnerLock.Lock()
+	defer wrapper.runnerLock.Unlock()
+	wrapper.addRunnerEventLocked()
+}
+
+// addRunnerEventLocked adds the runner event once, wrapper.runnerLock must be held
+func (wrapper *GenericWrapper) addRunnerEventLocked() {
+	if wrapper.dontAddRunner || wrapper.runnerAdded {
 		return
 	}
-	endTime := tracer.GetTimestamp()
+	wrapper.runnerAdded = true
+	endTime := tracer.GetTracerTimestamp(wrapper.tracer)
 	wrapper.runner.Duration = endTime - wrapper.runner.StartTime
+	if wrapper.runtimeStats != nil {
+		wrapper.runtimeStats.AddRuntimeMetrics(wrapper.runner)
+	}
 	wrapper.tracer.AddEvent(wrapper.runner)
 }
 
@@ -99,7 +145,7 @@ func (wrapper *GenericWrapper) transformArguments(args ...interface{}) []reflect
 		wrapper.runner.Exception = &protocol.Exception{
 			Type:    "Runtime Error",
 			Message: fmt.Sprintf("%v", msg),
-			Time:    tracer.GetTimestamp(),
+			Time:    tracer.GetTracerTimestamp(wrapper.tracer),
 		}
 		wrapper.safeAddRunnerEvent()
 		panic(msg)
@@ -123,16 +169,10 @@ func (wrapper *GenericWrapper) Call(args ...interface{}) (results []reflect.Valu
 	defer func() {
 		wrapper.thrownError = recover()
 		if wrapper.thrownError != nil {
-			exception := &protocol.Exception{
-				Type:      "Runtime Error",
-				Message:   fmt.Sprintf("%v", wrapper.thrownError),
-				Traceback: string(debug.Stack()),
-				Time:      tracer.GetTimestamp(),
-			}
+			exception := tracer.NewTracerException(wrapper.tracer,
+				"Runtime Error", fmt.Sprintf("%v", wrapper.thrownError), wrapper.thrownError)
 			if wrapper.invoking {
-				wrapper.runner.Exception = exception
-				wrapper.runner.ErrorCode = protocol.ErrorCode_EXCEPTION
-				wrapper.safeAddRunnerEvent()
+				wrapper.safeAddRunnerException(exception)
 				panic(userError{
 					exception: wrapper.thrownError,
 					stack:     exception.Traceback,
@@ -148,6 +188,8 @@ func (wrapper *GenericWrapper) Call(args ...interface{}) (results []reflect.Valu
 	}()
 
 	wrapper.createRunner()
+	registerActiveWrapper(wrapper)
+	defer unregisterActiveWrapper(wrapper)
 	wrapper.invoking = true
 	wrapper.invoked = true
 	results = wrapper.handler.Call(inputs)
@@ -2,6 +2,7 @@ package epsagon
 
 import (
 	"reflect"
+	"runtime"
 
 	"github.com/epsagon/epsagon-go/protocol"
 	"github.com/epsagon/epsagon-go/tracer"
@@ -75,6 +76,32 @@ var _ = Describe("generic_wrapper", func() {
 				Expect(result).To(Equal(true))
 				Expect(len(events)).To(Equal(1))
 			})
+			It("Adds runtime metrics when enabled", func() {
+				config := &Config{}
+				config.RuntimeMetrics = true
+				wrapper := &GenericWrapper{
+					config:  config,
+					handler: reflect.ValueOf(func() { runtime.GC() }),
+					tracer:  tracer.GlobalTracer,
+				}
+				wrapper.Call()
+				Expect(len(events)).To(Equal(1))
+				metadata := events[0].Resource.Metadata
+				Expect(metadata).To(HaveKey(tracer.GoroutinesKey))
+				Expect(metadata).To(HaveKey(tracer.HeapAllocKey))
+				Expect(metadata).To(HaveKey(tracer.HeapSysKey))
+				Expect(metadata[tracer.GCCountKey]).NotTo(Equal("0"))
+				Expect(metadata).To(HaveKey(tracer.GCPauseKey))
+			})
+			It("Does not add runtime metrics by default", func() {
+				wrapper := &GenericWrapper{
+					config:  &Config{},
+					handler: reflect.ValueOf(func() {}),
+					tracer:  tracer.GlobalTracer,
+				}
+				wrapper.Call()
+				Expect(events[0].Resource.Metadata).NotTo(HaveKey(tracer.GoroutinesKey))
+			})
 		})
 		Context("Error Flows", func() {
 			var (
@@ -7,7 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
-	"runtime/debug"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -16,20 +16,18 @@ import (
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/epsagon/epsagon-go/protocol"
 	"github.com/epsagon/epsagon-go/tracer"
-	"github.com/google/uuid"
 )
 
-type triggerFactory func(event interface{}, metadataOnly bool) *protocol.Event
+// TriggerFactory creates the trigger event of a decoded Lambda event, or nil if
+// the event has no trigger. Payloads and request bodies should only be added
+// when metadataOnly is false
+type TriggerFactory func(event interface{}, metadataOnly bool) *protocol.Event
 
 func mapParametersToString(params map[string]string) string {
 	buf, err := json.Marshal(params)
 	if err != nil {
-		tracer.AddException(&protocol.Exception{
-			Type:      "trigger-creation",
-			Message:   fmt.Sprintf("Failed to serialize %v", params),
-			Traceback: string(debug.Stack()),
-			Time:      tracer.GetTimestamp(),
-		})
+		tracer.AddException(tracer.NewException(
+			"trigger-creation", fmt.Sprintf("Failed to serialize %v", params), err))
 		return ""
 	}
 	return string(buf)
@@ -84,12 +82,8 @@ func getAPIGatewayBaseEvent(eventFields *APIGatewayEventFields) *protocol.Event
 
 func addAPIGatewayRequestData(triggerEvent *protocol.Event, eventFields *APIGatewayEventFields) {
 	if bodyJSON, err := json.Marshal(eventFields.body); err != nil {
-		tracer.AddException(&protocol.Exception{
-			Type:      "trigger-creation",
-			Message:   fmt.Sprintf("Failed to serialize body %s", eventFields.body),
-			Traceback: string(debug.Stack()),
-			Time:      tracer.GetTimestamp(),
-		})
+		tracer.AddException(tracer.NewException(
+			"trigger-creation", fmt.Sprintf("Failed to serialize body %s", eventFields.body), err))
 		triggerEvent.Resource.Metadata["body"] = ""
 	} else {
 		triggerEvent.Resource.Metadata["body"] = string(bodyJSON)
@@ -137,6 +131,259 @@ func triggerAPIGatewayV2HTTPRequest(rawEvent interface{}, metadataOnly bool) *pr
 	}, metadataOnly)
 }
 
+func triggerAPIGatewayWebsocketProxyRequest(rawEvent interface{}, metadataOnly bool) *protocol.Event {
+	event, ok := rawEvent.(lambdaEvents.APIGatewayWebsocketProxyRequest)
+	if !ok {
+		assertionException := getEventAssertionException(rawEvent, "lambdaEvents.APIGatewayWebsocketProxyRequest")
+		tracer.AddException(assertionException)
+		return nil
+	}
+
+	triggerEvent := &protocol.Event{
+		Id:        event.RequestContext.RequestID,
+		Origin:    "trigger",
+		StartTime: tracer.GetTimestamp(),
+		Resource: &protocol.Resource{
+			Name:      event.RequestContext.DomainName,
+			Type:      "api_gateway_websocket",
+			Operation: event.RequestContext.EventType,
+			Metadata: map[string]string{
+				"stage":         event.RequestContext.Stage,
+				"api_id":        event.RequestContext.APIID,
+				"connection_id": event.RequestContext.ConnectionID,
+				"route_key":     event.RequestContext.RouteKey,
+				"event_type":    event.RequestContext.EventType,
+			},
+		},
+	}
+	if event.RequestContext.MessageID != nil {
+		triggerEvent.Resource.Metadata["message_id"] = fmt.Sprintf("%v", event.RequestContext.MessageID)
+	}
+
+	if !metadataOnly {
+		triggerEvent.Resource.Metadata["body"] = event.Body
+	}
+
+	return triggerEvent
+}
+
+func triggerLambdaFunctionURLRequest(rawEvent interface{}, metadataOnly bool) *protocol.Event {
+	// function URLs send API Gateway HTTP API (v2) payloads
+	event, ok := rawEvent.(lambdaEvents.APIGatewayV2HTTPRequest)
+	if !ok {
+		assertionException := getEventAssertionException(rawEvent, "lambdaEvents.APIGatewayV2HTTPRequest")
+		tracer.AddException(assertionException)
+		return nil
+	}
+
+	triggerEvent := &protocol.Event{
+		Id:        event.RequestContext.RequestID,
+		Origin:    "trigger",
+		StartTime: tracer.GetTimestamp(),
+		Resource: &protocol.Resource{
+			Name:      event.RequestContext.DomainName,
+			Type:      "lambda_function_url",
+			Operation: event.RequestContext.HTTP.Method,
+			Metadata: map[string]string{
+				"path":                    event.RawPath,
+				"query_string_parameters": mapParametersToString(event.QueryStringParameters),
+				"source_ip":               event.RequestContext.HTTP.SourceIP,
+				"user_agent":              event.RequestContext.HTTP.UserAgent,
+			},
+		},
+	}
+
+	if !metadataOnly {
+		triggerEvent.Resource.Metadata["headers"] = mapParametersToString(event.Headers)
+		triggerEvent.Resource.Metadata["body"] = event.Body
+	}
+
+	return triggerEvent
+}
+
+// apiGatewayNoProxyRequest is the event created by the API Gateway
+// "Method Request passthrough" mapping template of non-proxy integrations
+type apiGatewayNoProxyRequest struct {
+	BodyJSON json.RawMessage `json:"body-json"`
+	Params   struct {
+		Path        map[string]string `json:"path"`
+		QueryString map[string]string `json:"querystring"`
+		Header      map[string]string `json:"header"`
+	} `json:"params"`
+	Context struct {
+		HTTPMethod   string `json:"http-method"`
+		ResourcePath string `json:"resource-path"`
+		Stage        string `json:"stage"`
+		RequestID    string `json:"request-id"`
+	} `json:"context"`
+}
+
+func triggerAPIGatewayNoProxyRequest(rawEvent interface{}, metadataOnly bool) *protocol.Event {
+	event, ok := rawEvent.(apiGatewayNoProxyRequest)
+	if !ok {
+		assertionException := getEventAssertionException(rawEvent, "apiGatewayNoProxyRequest")
+		tracer.AddException(assertionException)
+		return nil
+	}
+	return getAPIGatewayTriggerEvent(&APIGatewayEventFields{
+		requestId:             event.Context.RequestID,
+		headers:               event.Params.Header,
+		host:                  event.Params.Header["Host"],
+		httpMethod:            event.Context.HTTPMethod,
+		stage:                 event.Context.Stage,
+		queryStringParameters: event.Params.QueryString,
+		pathParameters:        event.Params.Path,
+		path:                  event.Context.ResourcePath,
+		body:                  string(event.BodyJSON),
+	}, metadataOnly)
+}
+
+func triggerALBTargetGroupRequest(rawEvent interface{}, metadataOnly bool) *protocol.Event {
+	event, ok := rawEvent.(lambdaEvents.ALBTargetGroupRequest)
+	if !ok {
+		assertionException := getEventAssertionException(rawEvent, "lambdaEvents.ALBTargetGroupRequest")
+		tracer.AddException(assertionException)
+		return nil
+	}
+
+	// target group ARNs look like arn:aws:elasticloadbalancing:region:account:targetgroup/name/id
+	targetGroupArn := event.RequestContext.ELB.TargetGroupArn
+	targetGroup := targetGroupArn
+	if targetGroupArnSlice := strings.Split(targetGroupArn, "/"); len(targetGroupArnSlice) >= 2 {
+		targetGroup = targetGroupArnSlice[1]
+	}
+
+	triggerEvent := &protocol.Event{
+		Id:        event.Headers["x-amzn-trace-id"],
+		Origin:    "trigger",
+		StartTime: tracer.GetTimestamp(),
+		Resource: &protocol.Resource{
+			Name:      targetGroup,
+			Type:      "elb",
+			Operation: event.HTTPMethod,
+			Metadata: map[string]string{
+				"target_group_arn":        targetGroupArn,
+				"path":                    event.Path,
+				"query_string_parameters": mapParametersToString(event.QueryStringParameters),
+			},
+		},
+	}
+
+	if !metadataOnly {
+		triggerEvent.Resource.Metadata["headers"] = mapParametersToString(event.Headers)
+		triggerEvent.Resource.Metadata["body"] = event.Body
+	}
+
+	return triggerEvent
+}
+
+// addTriggerResponseData adds the response returned by the handler to the trigger event
+func addTriggerResponseData(triggerEvent *protocol.Event, result interface{}) {
+	if triggerEvent == nil || triggerEvent.Resource == nil {
+		return
+	}
+	switch response := result.(type) {
+	case lambdaEvents.ALBTargetGroupResponse:
+		triggerEvent.Resource.Metadata["status_code"] = strconv.Itoa(response.StatusCode)
+	case *lambdaEvents.ALBTargetGroupResponse:
+		if response != nil {
+			triggerEvent.Resource.Metadata["status_code"] = strconv.Itoa(response.StatusCode)
+		}
+	}
+}
+
+// batchResponse is the partial batch response of SQS, Kinesis and DynamoDB
+// streams handlers, like events.SQSEventResponse
+type batchResponse struct {
+	BatchItemFailures []struct {
+		ItemIdentifier string `json:"itemIdentifier"`
+	} `json:"batchItemFailures"`
+}
+
+var partialBatchTriggerTypes = map[string]bool{
+	"sqs":      true,
+	"kinesis":  true,
+	"dynamodb": true,
+}
+
+// addBatchItemFailures adds the failed items of a partial batch response to
+// the runner event, and marks it as an error when any item failed
+func addBatchItemFailures(runnerEvent *protocol.Event, triggerEvent *protocol.Event, result interface{}) {
+	if result == nil || triggerEvent == nil || triggerEvent.Resource == nil ||
+		!partialBatchTriggerTypes[triggerEvent.Resource.Type] {
+		return
+	}
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return
+	}
+	var response batchResponse
+	if err := json.Unmarshal(resultJSON, &response); err != nil || len(response.BatchItemFailures) == 0 {
+		return
+	}
+
+	failedItems := make([]string, 0, len(response.BatchItemFailures))
+	for _, failure := range response.BatchItemFailures {
+		failedItems = append(failedItems, failure.ItemIdentifier)
+	}
+	if failedItemsJSON, err := json.Marshal(failedItems); err == nil {
+		runnerEvent.Resource.Metadata["batch_item_failures"] = string(failedItemsJSON)
+	}
+	runnerEvent.Resource.Metadata["failed_items_count"] = strconv.Itoa(len(failedItems))
+
+	if runnerEvent.ErrorCode == protocol.ErrorCode_OK {
+		runnerEvent.ErrorCode = protocol.ErrorCode_ERROR
+		if runnerEvent.Exception == nil {
+			runnerEvent.Exception = &protocol.Exception{
+				Type: "Partial Batch Failure",
+				Message: fmt.Sprintf("%d of %s batch items failed",
+					len(failedItems), triggerEvent.Resource.Metadata["batch_size"]),
+				Time: tracer.GetTimestamp(),
+			}
+		}
+	}
+}
+
+// lastArnSegment returns the segment of the ARN at the given position from the end
+func lastArnSegment(arn, separator string, position int) string {
+	arnSlice := strings.Split(arn, separator)
+	if len(arnSlice) < position {
+		return ""
+	}
+	return arnSlice[len(arnSlice)-position]
+}
+
+// maxSummarizedRecords is the maximum number of records summarized in the
+// metadata of a batched trigger
+const maxSummarizedRecords = 50
+
+// addBatchMetadata adds the batch size and a summary of the first records of a
+// batched trigger, and the record sources when they differ across records
+func addBatchMetadata(triggerEvent *protocol.Event, records []map[string]string, sources []string) {
+	metadata := triggerEvent.Resource.Metadata
+	metadata["batch_size"] = strconv.Itoa(len(records))
+	if len(records) > maxSummarizedRecords {
+		records = records[:maxSummarizedRecords]
+	}
+	if recordsJSON, err := json.Marshal(records); err == nil {
+		metadata["records"] = string(recordsJSON)
+	}
+
+	distinctSources := make([]string, 0, 1)
+	seen := make(map[string]bool)
+	for _, source := range sources {
+		if !seen[source] {
+			seen[source] = true
+			distinctSources = append(distinctSources, source)
+		}
+	}
+	if len(distinctSources) > 1 {
+		if sourcesJSON, err := json.Marshal(distinctSources); err == nil {
+			metadata["sources"] = string(sourcesJSON)
+		}
+	}
+}
+
 func triggerS3Event(rawEvent interface{}, metadataOnly bool) *protocol.Event {
 	event, ok := rawEvent.(lambdaEvents.S3Event)
 	if !ok {
@@ -169,6 +416,17 @@ func triggerS3Event(rawEvent interface{}, metadataOnly bool) *protocol.Event {
 		},
 	}
 
+	records := make([]map[string]string, 0, len(event.Records))
+	buckets := make([]string, 0, len(event.Records))
+	for _, record := range event.Records {
+		records = append(records, map[string]string{
+			"event_name": record.EventName,
+			"object_key": record.S3.Object.Key,
+		})
+		buckets = append(buckets, record.S3.Bucket.Name)
+	}
+	addBatchMetadata(triggerEvent, records, buckets)
+
 	return triggerEvent
 }
 
@@ -204,6 +462,18 @@ func triggerKinesisEvent(rawEvent interface{}, metadataOnly bool) *protocol.Even
 		},
 	}
 
+	records := make([]map[string]string, 0, len(event.Records))
+	streams := make([]string, 0, len(event.Records))
+	for _, record := range event.Records {
+		records = append(records, map[string]string{
+			"event_id":        record.EventID,
+			"sequence_number": record.Kinesis.SequenceNumber,
+			"partition_key":   record.Kinesis.PartitionKey,
+		})
+		streams = append(streams, lastArnSegment(record.EventSourceArn, "/", 1))
+	}
+	addBatchMetadata(triggerEvent, records, streams)
+
 	return triggerEvent
 }
 
@@ -240,6 +510,16 @@ func triggerSNSEvent(rawEvent interface{}, metadataOnly bool) *protocol.Event {
 		triggerEvent.Resource.Metadata["Notification Message"] = event.Records[0].SNS.Message
 	}
 
+	records := make([]map[string]string, 0, len(event.Records))
+	topics := make([]string, 0, len(event.Records))
+	for _, record := range event.Records {
+		records = append(records, map[string]string{
+			"message_id": record.SNS.MessageID,
+		})
+		topics = append(topics, lastArnSegment(record.EventSubscriptionArn, ":", 2))
+	}
+	addBatchMetadata(triggerEvent, records, topics)
+
 	return triggerEvent
 }
 
@@ -283,6 +563,22 @@ func triggerSQSEvent(rawEvent interface{}, metadataOnly bool) *protocol.Event {
 		}
 	}
 
+	records := make([]map[string]string, 0, len(event.Records))
+	queues := make([]string, 0, len(event.Records))
+	messageIDs := make([]string, 0, len(event.Records))
+	for _, record := range event.Records {
+		records = append(records, map[string]string{
+			"message_id":                record.MessageId,
+			"approximate_receive_count": record.Attributes["ApproximateReceiveCount"],
+		})
+		queues = append(queues, lastArnSegment(record.EventSourceARN, ":", 1))
+		messageIDs = append(messageIDs, record.MessageId)
+	}
+	addBatchMetadata(triggerEvent, records, queues)
+	if messageIDsJSON, err := json.Marshal(messageIDs); err == nil {
+		triggerEvent.Resource.Metadata["message_ids"] = string(messageIDsJSON)
+	}
+
 	return triggerEvent
 }
 
@@ -347,6 +643,18 @@ func triggerDynamoDBEvent(rawEvent interface{}, metadataOnly bool) *protocol.Eve
 		},
 	}
 
+	records := make([]map[string]string, 0, len(event.Records))
+	tables := make([]string, 0, len(event.Records))
+	for _, record := range event.Records {
+		records = append(records, map[string]string{
+			"event_id":        record.EventID,
+			"event_name":      record.EventName,
+			"sequence_number": record.Change.SequenceNumber,
+		})
+		tables = append(tables, lastArnSegment(record.EventSourceArn, "/", 3))
+	}
+	addBatchMetadata(triggerEvent, records, tables)
+
 	itemBytes, err := getImageMapBytes(event.Records[0].Change.NewImage)
 	if err != nil {
 		return triggerEvent
@@ -368,9 +676,425 @@ func triggerDynamoDBEvent(rawEvent interface{}, metadataOnly bool) *protocol.Eve
 	return triggerEvent
 }
 
+func triggerCloudWatchEvent(rawEvent interface{}, metadataOnly bool) *protocol.Event {
+	event, ok := rawEvent.(lambdaEvents.CloudWatchEvent)
+	if !ok {
+		assertionException := getEventAssertionException(rawEvent, "lambdaEvents.CloudWatchEvent")
+		tracer.AddException(assertionException)
+		return nil
+	}
+
+	// rule ARNs look like arn:aws:events:region:account:rule/[event-bus/]rule-name
+	rule := ""
+	if len(event.Resources) > 0 {
+		ruleArnSlice := strings.Split(event.Resources[0], "/")
+		rule = ruleArnSlice[len(ruleArnSlice)-1]
+	}
+	name := rule
+	if len(name) == 0 {
+		name = event.Source
+	}
+
+	triggerEvent := &protocol.Event{
+		Id:        event.ID,
+		Origin:    "trigger",
+		StartTime: tracer.GetTimestamp(),
+		Resource: &protocol.Resource{
+			Name:      name,
+			Type:      "events",
+			Operation: event.DetailType,
+			Metadata: map[string]string{
+				"rule":        rule,
+				"source":      event.Source,
+				"detail_type": event.DetailType,
+				"account":     event.AccountID,
+				"region":      event.Region,
+				"event_id":    event.ID,
+			},
+		},
+	}
+
+	if !metadataOnly {
+		triggerEvent.Resource.Metadata["detail"] = string(event.Detail)
+	}
+
+	return triggerEvent
+}
+
+func triggerCloudwatchLogsEvent(rawEvent interface{}, metadataOnly bool) *protocol.Event {
+	event, ok := rawEvent.(lambdaEvents.CloudwatchLogsEvent)
+	if !ok {
+		assertionException := getEventAssertionException(rawEvent, "lambdaEvents.CloudwatchLogsEvent")
+		tracer.AddException(assertionException)
+		return nil
+	}
+	logsData, err := event.AWSLogs.Parse()
+	if err != nil {
+		tracer.AddException(tracer.NewException(
+			"trigger-creation", "Failed to decode CloudWatch Logs data", err))
+		return nil
+	}
+
+	eventID := ""
+	if len(logsData.LogEvents) > 0 {
+		eventID = logsData.LogEvents[0].ID
+	}
+
+	triggerEvent := &protocol.Event{
+		Id:        eventID,
+		Origin:    "trigger",
+		StartTime: tracer.GetTimestamp(),
+		Resource: &protocol.Resource{
+			Name:      logsData.LogGroup,
+			Type:      "cloudwatch_logs",
+			Operation: logsData.MessageType,
+			Metadata: map[string]string{
+				"log_group":            logsData.LogGroup,
+				"log_stream":           logsData.LogStream,
+				"log_events_count":     strconv.Itoa(len(logsData.LogEvents)),
+				"owner":                logsData.Owner,
+				"subscription_filters": strings.Join(logsData.SubscriptionFilters, ","),
+			},
+		},
+	}
+
+	if !metadataOnly {
+		messages := make([]string, 0, len(logsData.LogEvents))
+		for _, logEvent := range logsData.LogEvents {
+			messages = append(messages, logEvent.Message)
+		}
+		if messagesJSON, err := json.Marshal(messages); err == nil {
+			triggerEvent.Resource.Metadata["log_events"] = string(messagesJSON)
+		}
+	}
+
+	return triggerEvent
+}
+
+func triggerKinesisFirehoseEvent(rawEvent interface{}, metadataOnly bool) *protocol.Event {
+	event, ok := rawEvent.(lambdaEvents.KinesisFirehoseEvent)
+	if !ok {
+		assertionException := getEventAssertionException(rawEvent, "lambdaEvents.KinesisFirehoseEvent")
+		tracer.AddException(assertionException)
+		return nil
+	}
+
+	return &protocol.Event{
+		Id:        event.InvocationID,
+		Origin:    "trigger",
+		StartTime: tracer.GetTimestamp(),
+		Resource: &protocol.Resource{
+			Name:      lastArnSegment(event.DeliveryStreamArn, "/", 1),
+			Type:      "firehose",
+			Operation: "transform",
+			Metadata: map[string]string{
+				"delivery_stream_arn":       event.DeliveryStreamArn,
+				"source_kinesis_stream_arn": event.SourceKinesisStreamArn,
+				"region":                    event.Region,
+				"record_count":              strconv.Itoa(len(event.Records)),
+			},
+		},
+	}
+}
+
+func triggerCognitoUserPoolsEvent(rawEvent interface{}, metadataOnly bool) *protocol.Event {
+	event, ok := rawEvent.(lambdaEvents.CognitoEventUserPoolsHeader)
+	if !ok {
+		assertionException := getEventAssertionException(rawEvent, "lambdaEvents.CognitoEventUserPoolsHeader")
+		tracer.AddException(assertionException)
+		return nil
+	}
+
+	return &protocol.Event{
+		Id:        "",
+		Origin:    "trigger",
+		StartTime: tracer.GetTimestamp(),
+		Resource: &protocol.Resource{
+			Name:      event.UserPoolID,
+			Type:      "cognito",
+			Operation: event.TriggerSource,
+			Metadata: map[string]string{
+				"trigger_source": event.TriggerSource,
+				"user_pool_id":   event.UserPoolID,
+				"user_name":      event.UserName,
+				"region":         event.Region,
+				"client_id":      event.CallerContext.ClientID,
+			},
+		},
+	}
+}
+
+// kafkaEvent is the event of MSK and self-managed Kafka event sources, its
+// records are keyed by topic-partition
+type kafkaEvent struct {
+	EventSource      string `json:"eventSource"`
+	EventSourceArn   string `json:"eventSourceArn"`
+	BootstrapServers string `json:"bootstrapServers"`
+	Records          map[string][]struct {
+		Topic     string `json:"topic"`
+		Partition int64  `json:"partition"`
+		Offset    int64  `json:"offset"`
+	} `json:"records"`
+}
+
+func triggerKafkaEvent(rawEvent interface{}, metadataOnly bool) *protocol.Event {
+	event, ok := rawEvent.(kafkaEvent)
+	if !ok {
+		assertionException := getEventAssertionException(rawEvent, "kafkaEvent")
+		tracer.AddException(assertionException)
+		return nil
+	}
+
+	partitions := make([]string, 0, len(event.Records))
+	for partition := range event.Records {
+		partitions = append(partitions, partition)
+	}
+	sort.Strings(partitions)
+
+	topics := make([]string, 0, 1)
+	seenTopics := make(map[string]bool)
+	offsets := make(map[string][]int64, len(partitions))
+	messageCount := 0
+	for _, partition := range partitions {
+		for _, record := range event.Records[partition] {
+			if !seenTopics[record.Topic] {
+				seenTopics[record.Topic] = true
+				topics = append(topics, record.Topic)
+			}
+			offsets[partition] = append(offsets[partition], record.Offset)
+			messageCount++
+		}
+	}
+
+	// MSK cluster ARNs look like arn:aws:kafka:region:account:cluster/name/id
+	cluster := lastArnSegment(event.EventSourceArn, "/", 2)
+	if len(cluster) == 0 {
+		cluster = event.BootstrapServers
+	}
+
+	triggerEvent := &protocol.Event{
+		Id:        "",
+		Origin:    "trigger",
+		StartTime: tracer.GetTimestamp(),
+		Resource: &protocol.Resource{
+			Name:      strings.Join(topics, ","),
+			Type:      "kafka",
+			Operation: "consume",
+			Metadata: map[string]string{
+				"event_source":      event.EventSource,
+				"event_source_arn":  event.EventSourceArn,
+				"cluster":           cluster,
+				"bootstrap_servers": event.BootstrapServers,
+				"topics":            strings.Join(topics, ","),
+				"partitions":        strings.Join(partitions, ","),
+				"message_count":     strconv.Itoa(messageCount),
+			},
+		},
+	}
+	if offsetsJSON, err := json.Marshal(offsets); err == nil {
+		triggerEvent.Resource.Metadata["offsets"] = string(offsetsJSON)
+	}
+
+	return triggerEvent
+}
+
+type mqMessage struct {
+	MessageID   string `json:"messageID"`
+	Destination struct {
+		PhysicalName string `json:"physicalName"`
+	} `json:"destination"`
+	BasicProperties struct {
+		MessageID string `json:"messageId"`
+	} `json:"basicProperties"`
+}
+
+// mqEvent is the event of Amazon MQ event sources, ActiveMQ messages are in
+// Messages and RabbitMQ messages are keyed by queue in RMQMessagesByQueue
+type mqEvent struct {
+	EventSource        string                 `json:"eventSource"`
+	EventSourceArn     string                 `json:"eventSourceArn"`
+	Messages           []mqMessage            `json:"messages"`
+	RMQMessagesByQueue map[string][]mqMessage `json:"rmqMessagesByQueue"`
+}
+
+func triggerMQEvent(rawEvent interface{}, metadataOnly bool) *protocol.Event {
+	event, ok := rawEvent.(mqEvent)
+	if !ok {
+		assertionException := getEventAssertionException(rawEvent, "mqEvent")
+		tracer.AddException(assertionException)
+		return nil
+	}
+
+	engine := "activemq"
+	queues := make([]string, 0, 1)
+	seenQueues := make(map[string]bool)
+	messageIDs := make([]string, 0, len(event.Messages))
+	for _, message := range event.Messages {
+		if queue := message.Destination.PhysicalName; !seenQueues[queue] {
+			seenQueues[queue] = true
+			queues = append(queues, queue)
+		}
+		messageIDs = append(messageIDs, message.MessageID)
+	}
+	if event.EventSource == "aws:rmq" {
+		engine = "rabbitmq"
+		for queue := range event.RMQMessagesByQueue {
+			queues = append(queues, queue)
+		}
+		sort.Strings(queues)
+		for _, queue := range queues {
+			for _, message := range event.RMQMessagesByQueue[queue] {
+				messageIDs = append(messageIDs, message.BasicProperties.MessageID)
+			}
+		}
+	}
+
+	triggerEvent := &protocol.Event{
+		Id:        "",
+		Origin:    "trigger",
+		StartTime: tracer.GetTimestamp(),
+		Resource: &protocol.Resource{
+			Name:      strings.Join(queues, ","),
+			Type:      "amazon_mq",
+			Operation: "consume",
+			Metadata: map[string]string{
+				"event_source":     event.EventSource,
+				"event_source_arn": event.EventSourceArn,
+				// broker ARNs look like arn:aws:mq:region:account:broker:name:id
+				"broker":        lastArnSegment(event.EventSourceArn, ":", 2),
+				"engine":        engine,
+				"queues":        strings.Join(queues, ","),
+				"message_count": strconv.Itoa(len(messageIDs)),
+			},
+		},
+	}
+	if len(messageIDs) > 0 {
+		triggerEvent.Id = messageIDs[0]
+	}
+	if messageIDsJSON, err := json.Marshal(messageIDs); err == nil {
+		triggerEvent.Resource.Metadata["message_ids"] = string(messageIDsJSON)
+	}
+
+	return triggerEvent
+}
+
+// appSyncResolverEvent is the event of AppSync direct Lambda resolvers
+type appSyncResolverEvent struct {
+	Arguments json.RawMessage `json:"arguments"`
+	Identity  json.RawMessage `json:"identity"`
+	Request   struct {
+		Headers map[string]string `json:"headers"`
+	} `json:"request"`
+	Info struct {
+		FieldName           string   `json:"fieldName"`
+		ParentTypeName      string   `json:"parentTypeName"`
+		SelectionSetList    []string `json:"selectionSetList"`
+		SelectionSetGraphQL string   `json:"selectionSetGraphQL"`
+	} `json:"info"`
+}
+
+func triggerAppSyncResolverEvent(rawEvent interface{}, metadataOnly bool) *protocol.Event {
+	event, ok := rawEvent.(appSyncResolverEvent)
+	if !ok {
+		assertionException := getEventAssertionException(rawEvent, "appSyncResolverEvent")
+		tracer.AddException(assertionException)
+		return nil
+	}
+
+	host := event.Request.Headers["host"]
+	name := host
+	if len(name) == 0 {
+		name = event.Info.ParentTypeName
+	}
+	selectionSet := event.Info.SelectionSetGraphQL
+	if len(selectionSet) == 0 {
+		selectionSet = strings.Join(event.Info.SelectionSetList, ",")
+	}
+
+	triggerEvent := &protocol.Event{
+		Id:        event.Request.Headers["x-amzn-requestid"],
+		Origin:    "trigger",
+		StartTime: tracer.GetTimestamp(),
+		Resource: &protocol.Resource{
+			Name:      name,
+			Type:      "appsync",
+			Operation: event.Info.FieldName,
+			Metadata: map[string]string{
+				"host":             host,
+				"parent_type_name": event.Info.ParentTypeName,
+				"field_name":       event.Info.FieldName,
+				"selection_set":    selectionSet,
+			},
+		},
+	}
+
+	if !metadataOnly {
+		triggerEvent.Resource.Metadata["arguments"] = string(event.Arguments)
+		triggerEvent.Resource.Metadata["identity"] = string(event.Identity)
+	}
+
+	return triggerEvent
+}
+
+type stepFunctionsContext struct {
+	Execution struct {
+		ID        string `json:"Id"`
+		Name      string `json:"Name"`
+		StartTime string `json:"StartTime"`
+	} `json:"Execution"`
+	StateMachine struct {
+		ID   string `json:"Id"`
+		Name string `json:"Name"`
+	} `json:"StateMachine"`
+	State struct {
+		Name       string `json:"Name"`
+		RetryCount int    `json:"RetryCount"`
+	} `json:"State"`
+}
+
+// stepFunctionsEvent is the input of a Step Functions task, with the context
+// object passed as the input or in its context field
+type stepFunctionsEvent struct {
+	stepFunctionsContext
+	Context *stepFunctionsContext `json:"context"`
+}
+
+func triggerStepFunctionsEvent(rawEvent interface{}, metadataOnly bool) *protocol.Event {
+	event, ok := rawEvent.(stepFunctionsEvent)
+	if !ok {
+		assertionException := getEventAssertionException(rawEvent, "stepFunctionsEvent")
+		tracer.AddException(assertionException)
+		return nil
+	}
+
+	contextObject := event.stepFunctionsContext
+	if event.Context != nil && isStepFunctionsContext(event.Context.Execution.ID) {
+		contextObject = *event.Context
+	}
+
+	return &protocol.Event{
+		Id:        contextObject.Execution.ID,
+		Origin:    "trigger",
+		StartTime: tracer.GetTimestamp(),
+		Resource: &protocol.Resource{
+			Name:      contextObject.StateMachine.Name,
+			Type:      "step_function",
+			Operation: contextObject.State.Name,
+			Metadata: map[string]string{
+				"execution_arn":        contextObject.Execution.ID,
+				"execution_name":       contextObject.Execution.Name,
+				"execution_start_time": contextObject.Execution.StartTime,
+				"state_machine_arn":    contextObject.StateMachine.ID,
+				"state_name":           contextObject.State.Name,
+				"retry_count":          strconv.Itoa(contextObject.State.RetryCount),
+			},
+		},
+	}
+}
+
 func triggerJSONEvent(rawEvent json.RawMessage, metadataOnly bool) *protocol.Event {
 	triggerEvent := &protocol.Event{
-		Id:        uuid.New().String(),
+		Id:        tracer.NewID(),
 		Origin:    "trigger",
 		StartTime: tracer.GetTimestamp(),
 		Resource: &protocol.Resource{
@@ -390,7 +1114,7 @@ func triggerJSONEvent(rawEvent json.RawMessage, metadataOnly bool) *protocol.Eve
 
 type factoryAndType struct {
 	EventType reflect.Type
-	Factory   triggerFactory
+	Factory   TriggerFactory
 }
 
 var (
@@ -399,10 +1123,26 @@ var (
 			EventType: reflect.TypeOf(lambdaEvents.APIGatewayProxyRequest{}),
 			Factory:   triggerAPIGatewayProxyRequest,
 		},
+		"api_gateway_no_proxy": {
+			EventType: reflect.TypeOf(apiGatewayNoProxyRequest{}),
+			Factory:   triggerAPIGatewayNoProxyRequest,
+		},
 		"api_gateway_http2": {
 			EventType: reflect.TypeOf(lambdaEvents.APIGatewayV2HTTPRequest{}),
 			Factory:   triggerAPIGatewayV2HTTPRequest,
 		},
+		"api_gateway_websocket": {
+			EventType: reflect.TypeOf(lambdaEvents.APIGatewayWebsocketProxyRequest{}),
+			Factory:   triggerAPIGatewayWebsocketProxyRequest,
+		},
+		"lambda_function_url": {
+			EventType: reflect.TypeOf(lambdaEvents.APIGatewayV2HTTPRequest{}),
+			Factory:   triggerLambdaFunctionURLRequest,
+		},
+		"elb": {
+			EventType: reflect.TypeOf(lambdaEvents.ALBTargetGroupRequest{}),
+			Factory:   triggerALBTargetGroupRequest,
+		},
 		"aws:s3": {
 			EventType: reflect.TypeOf(lambdaEvents.S3Event{}),
 			Factory:   triggerS3Event,
@@ -423,16 +1163,51 @@ var (
 			EventType: reflect.TypeOf(lambdaEvents.DynamoDBEvent{}),
 			Factory:   triggerDynamoDBEvent,
 		},
+		"events": {
+			EventType: reflect.TypeOf(lambdaEvents.CloudWatchEvent{}),
+			Factory:   triggerCloudWatchEvent,
+		},
+		"cloudwatch_logs": {
+			EventType: reflect.TypeOf(lambdaEvents.CloudwatchLogsEvent{}),
+			Factory:   triggerCloudwatchLogsEvent,
+		},
+		"firehose": {
+			EventType: reflect.TypeOf(lambdaEvents.KinesisFirehoseEvent{}),
+			Factory:   triggerKinesisFirehoseEvent,
+		},
+		"kafka": {
+			EventType: reflect.TypeOf(kafkaEvent{}),
+			Factory:   triggerKafkaEvent,
+		},
+		"amazon_mq": {
+			EventType: reflect.TypeOf(mqEvent{}),
+			Factory:   triggerMQEvent,
+		},
+		"appsync": {
+			EventType: reflect.TypeOf(appSyncResolverEvent{}),
+			Factory:   triggerAppSyncResolverEvent,
+		},
+		"step_functions": {
+			EventType: reflect.TypeOf(stepFunctionsEvent{}),
+			Factory:   triggerStepFunctionsEvent,
+		},
+		"cognito": {
+			EventType: reflect.TypeOf(lambdaEvents.CognitoEventUserPoolsHeader{}),
+			Factory:   triggerCognitoUserPoolsEvent,
+		},
 	}
 )
 
 func decodeAndUnpackEvent(
 	payload json.RawMessage,
 	eventType reflect.Type,
-	factory triggerFactory,
+	factory TriggerFactory,
 	metadataOnly bool,
 ) *protocol.Event {
 
+	if eventType == nil {
+		return factory(payload, metadataOnly)
+	}
 	event := reflect.New(eventType)
 	decoder := json.NewDecoder(bytes.NewReader(payload))
 
@@ -446,47 +1221,192 @@ type recordField struct {
 	EventSource string
 }
 
+// recordFields are the Records of an event, Kafka events have records keyed
+// by topic-partition instead, which are ignored
+type recordFields []recordField
+
+func (records *recordFields) UnmarshalJSON(data []byte) error {
+	var fields []recordField
+	if err := json.Unmarshal(data, &fields); err != nil {
+		*records = nil
+		return nil
+	}
+	*records = fields
+	return nil
+}
+
 type httpDescription struct {
 	Method string
 }
 
+type elbContext struct {
+	TargetGroupArn stringField
+}
+
+// UnmarshalJSON ignores elb fields that are not an object, like in
+// arbitrary JSON payloads
+func (field *elbContext) UnmarshalJSON(data []byte) error {
+	type plainField elbContext
+	var value plainField
+	if err := json.Unmarshal(data, &value); err != nil {
+		*field = elbContext{}
+		return nil
+	}
+	*field = elbContext(value)
+	return nil
+}
+
 type requestContext struct {
-	APIID string
-	HTTP  httpDescription
+	APIID        string
+	HTTP         httpDescription
+	ELB          elbContext
+	ConnectionID stringField
+	DomainName   stringField
+}
+
+type awsLogsField struct {
+	Data stringField
+}
+
+// UnmarshalJSON ignores awslogs fields that are not an object, like in
+// arbitrary JSON payloads
+func (field *awsLogsField) UnmarshalJSON(data []byte) error {
+	type plainField awsLogsField
+	var value plainField
+	if err := json.Unmarshal(data, &value); err != nil {
+		*field = awsLogsField{}
+		return nil
+	}
+	*field = awsLogsField(value)
+	return nil
+}
+
+// stringField is a string field that is ignored when it has another type,
+// like the source object of AppSync resolver events
+type stringField string
+
+func (field *stringField) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		*field = ""
+		return nil
+	}
+	*field = stringField(value)
+	return nil
+}
+
+type appSyncInfoField struct {
+	FieldName      stringField
+	ParentTypeName stringField
+}
+
+// UnmarshalJSON ignores info fields that are not an object, like in
+// arbitrary JSON payloads
+func (field *appSyncInfoField) UnmarshalJSON(data []byte) error {
+	type plainField appSyncInfoField
+	var value plainField
+	if err := json.Unmarshal(data, &value); err != nil {
+		*field = appSyncInfoField{}
+		return nil
+	}
+	*field = appSyncInfoField(value)
+	return nil
+}
+
+type executionField struct {
+	ID stringField
+}
+
+// UnmarshalJSON ignores execution fields that are not an object, like in
+// arbitrary JSON payloads
+func (field *executionField) UnmarshalJSON(data []byte) error {
+	type plainField executionField
+	var value plainField
+	if err := json.Unmarshal(data, &value); err != nil {
+		*field = executionField{}
+		return nil
+	}
+	*field = executionField(value)
+	return nil
+}
+
+// isStepFunctionsContext returns whether the execution ID is of a Step
+// Functions context object
+func isStepFunctionsContext(executionID string) bool {
+	return strings.HasPrefix(executionID, "arn:aws:states:")
+}
+
+// contextExecutionID returns the execution ID of a Step Functions context
+// object passed in the context field of the input
+func contextExecutionID(context map[string]interface{}) string {
+	execution, ok := context["Execution"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	executionID, _ := execution["Id"].(string)
+	return executionID
 }
 
 type interestingFields struct {
-	Records        []recordField
-	HTTPMethod     string
-	Context        map[string]interface{}
-	MethodArn      string
-	Source         string
-	RequestContext requestContext
+	Records           recordFields
+	EventSource       stringField
+	HTTPMethod        string
+	Context           map[string]interface{}
+	MethodArn         string
+	Source            stringField
+	DetailType        stringField `json:"detail-type"`
+	RequestContext    requestContext
+	AWSLogs           awsLogsField
+	DeliveryStreamArn stringField
+	TriggerSource     stringField
+	UserPoolID        stringField
+	Info              appSyncInfoField
+	Execution         executionField
 }
 
 func guessTriggerSource(payload json.RawMessage) string {
 	var rawEvent interestingFields
 	err := json.Unmarshal(payload, &rawEvent)
 	if err != nil {
-		tracer.AddException(&protocol.Exception{
-			Type:      "trigger-identification",
-			Message:   fmt.Sprintf("Failed to unmarshal json %v\n", err),
-			Traceback: string(debug.Stack()),
-			Time:      tracer.GetTimestamp(),
-		})
+		tracer.AddException(tracer.NewException(
+			"trigger-identification", fmt.Sprintf("Failed to unmarshal json %v\n", err), err))
 		return ""
 	}
 	triggerSource := "json"
-	if len(rawEvent.Records) > 0 {
+	if len(rawEvent.DeliveryStreamArn) > 0 {
+		// firehose records have no event source
+		triggerSource = "firehose"
+	} else if rawEvent.EventSource == "aws:kafka" || rawEvent.EventSource == "SelfManagedKafka" {
+		triggerSource = "kafka"
+	} else if rawEvent.EventSource == "aws:mq" || rawEvent.EventSource == "aws:rmq" {
+		triggerSource = "amazon_mq"
+	} else if len(rawEvent.Records) > 0 {
 		triggerSource = rawEvent.Records[0].EventSource
+	} else if len(rawEvent.RequestContext.ConnectionID) > 0 {
+		triggerSource = "api_gateway_websocket"
+	} else if strings.Contains(string(rawEvent.RequestContext.DomainName), ".lambda-url.") {
+		triggerSource = "lambda_function_url"
+	} else if len(rawEvent.RequestContext.ELB.TargetGroupArn) > 0 {
+		triggerSource = "elb"
 	} else if len(rawEvent.HTTPMethod) > 0 {
 		triggerSource = "api_gateway"
 	} else if _, ok := rawEvent.Context["http-method"]; ok {
 		triggerSource = "api_gateway_no_proxy"
+	} else if len(rawEvent.Info.FieldName) > 0 && len(rawEvent.Info.ParentTypeName) > 0 {
+		triggerSource = "appsync"
+	} else if isStepFunctionsContext(string(rawEvent.Execution.ID)) || isStepFunctionsContext(contextExecutionID(rawEvent.Context)) {
+		triggerSource = "step_functions"
 	} else if len(rawEvent.RequestContext.APIID) > 0 && len(rawEvent.RequestContext.HTTP.Method) > 0 {
 		triggerSource = "api_gateway_http2"
+	} else if len(rawEvent.AWSLogs.Data) > 0 {
+		triggerSource = "cloudwatch_logs"
+	} else if len(rawEvent.TriggerSource) > 0 && len(rawEvent.UserPoolID) > 0 {
+		triggerSource = "cognito"
+	} else if len(rawEvent.DetailType) > 0 {
+		// EventBridge and scheduled events, with any source
+		triggerSource = "events"
 	} else if len(rawEvent.Source) > 0 {
-		sourceSlice := strings.Split(rawEvent.Source, ".")
+		sourceSlice := strings.Split(string(rawEvent.Source), ".")
 		triggerSource = sourceSlice[len(sourceSlice)-1]
 	}
 	return triggerSource
@@ -497,15 +1417,14 @@ func addLambdaTrigger(
 	metadataOnly bool,
 	triggerFactories map[string]factoryAndType,
 	currentTracer tracer.Tracer,
-) {
+) *protocol.Event {
 	var triggerEvent *protocol.Event
 
-	triggerSource := guessTriggerSource(payload)
-
-	if triggerSource == "json" {
+	if customFactory, found := detectCustomTrigger(payload, currentTracer); found {
+		triggerEvent = decodeAndUnpackEvent(
+			payload, customFactory.EventType, customFactory.Factory, metadataOnly)
+	} else if triggerSource := guessTriggerSource(payload); triggerSource == "json" {
 		triggerEvent = triggerJSONEvent(payload, metadataOnly)
-	} else if triggerSource == "api_gateway_no_proxy" {
-		// currently not supported, needs to extract data from json
 	} else {
 		factoryStruct, found := triggerFactories[triggerSource]
 		if found {
@@ -518,4 +1437,5 @@ func addLambdaTrigger(
 	if triggerEvent != nil {
 		currentTracer.AddEvent(triggerEvent)
 	}
+	return triggerEvent
 }
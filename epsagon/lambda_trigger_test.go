@@ -2,7 +2,13 @@ package epsagon
 
 import (
 	// "fmt"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	lambdaEvents "github.com/aws/aws-lambda-go/events"
@@ -61,6 +67,122 @@ var (
 			"hello": "world",
 		},
 	}
+	exampleWebsocketMessage = lambdaEvents.APIGatewayWebsocketProxyRequest{
+		Body: "{\"action\":\"send\"}",
+		RequestContext: lambdaEvents.APIGatewayWebsocketProxyRequestContext{
+			Stage:        "prod",
+			RequestID:    "websocket-request-id",
+			APIID:        "websocket-api",
+			ConnectionID: "connection-id=",
+			DomainName:   "websocket-api.execute-api.us-east-1.amazonaws.com",
+			EventType:    "MESSAGE",
+			MessageID:    "message-id",
+			RouteKey:     "send",
+		},
+	}
+	exampleFunctionURL = lambdaEvents.APIGatewayV2HTTPRequest{
+		Version:  "2.0",
+		RouteKey: "$default",
+		RawPath:  "/hello",
+		Headers: map[string]string{
+			"host": "abcdefg.lambda-url.us-east-1.on.aws",
+		},
+		QueryStringParameters: map[string]string{
+			"hello": "world",
+		},
+		RequestContext: lambdaEvents.APIGatewayV2HTTPRequestContext{
+			RouteKey:   "$default",
+			APIID:      "abcdefg",
+			RequestID:  "function-url-request-id",
+			DomainName: "abcdefg.lambda-url.us-east-1.on.aws",
+			HTTP: lambdaEvents.APIGatewayV2HTTPRequestContextHTTPDescription{
+				Method:    "GET",
+				Path:      "/hello",
+				SourceIP:  "127.0.0.1",
+				UserAgent: "test",
+			},
+		},
+		Body: "hello world",
+	}
+	exampleAPIGatewayNoProxy = `{
+		"body-json": {"hello": "world"},
+		"params": {
+			"path": {"id": "1"},
+			"querystring": {"verbose": "true"},
+			"header": {"Host": "api.example.com", "User-Agent": "test"}
+		},
+		"stage-variables": {},
+		"context": {
+			"account-id": "123456789012",
+			"api-id": "test-api",
+			"http-method": "POST",
+			"stage": "prod",
+			"request-id": "no-proxy-request-id",
+			"resource-path": "/items/{id}"
+		}
+	}`
+	exampleALB = lambdaEvents.ALBTargetGroupRequest{
+		HTTPMethod: "POST",
+		Path:       "/orders",
+		QueryStringParameters: map[string]string{
+			"hello": "world",
+		},
+		Headers: map[string]string{
+			"host":            "my-alb.example.com",
+			"x-amzn-trace-id": "Root=1-5bdb40ca-556d8b0c50dc66f0511bf520",
+		},
+		RequestContext: lambdaEvents.ALBTargetGroupRequestContext{
+			ELB: lambdaEvents.ELBContext{
+				TargetGroupArn: "arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/my-targets/73e2d6bc24d8a067",
+			},
+		},
+		Body: "{\"order\":1}",
+	}
+	exampleSQSBatch = lambdaEvents.SQSEvent{
+		Records: []lambdaEvents.SQSMessage{
+			{
+				MessageId:      "message-1",
+				EventSource:    "aws:sqs",
+				EventSourceARN: "arn:aws:sqs:us-east-1:123456789012:orders",
+				Attributes:     map[string]string{"ApproximateReceiveCount": "1"},
+				Body:           "first",
+			},
+			{
+				MessageId:      "message-2",
+				EventSource:    "aws:sqs",
+				EventSourceARN: "arn:aws:sqs:us-east-1:123456789012:orders",
+				Attributes:     map[string]string{"ApproximateReceiveCount": "2"},
+				Body:           "second",
+			},
+			{
+				MessageId:      "message-3",
+				EventSource:    "aws:sqs",
+				EventSourceARN: "arn:aws:sqs:us-east-1:123456789012:refunds",
+				Attributes:     map[string]string{"ApproximateReceiveCount": "1"},
+				Body:           "third",
+			},
+		},
+	}
+	exampleS3Batch = lambdaEvents.S3Event{
+		Records: []lambdaEvents.S3EventRecord{
+			{
+				EventSource: "aws:s3",
+				EventName:   "ObjectCreated:Put",
+				S3: lambdaEvents.S3Entity{
+					Bucket: lambdaEvents.S3Bucket{Name: "uploads"},
+					Object: lambdaEvents.S3Object{Key: "first.txt"},
+				},
+			},
+			{
+				EventSource: "aws:s3",
+				EventName:   "ObjectRemoved:Delete",
+				S3: lambdaEvents.S3Entity{
+					Bucket: lambdaEvents.S3Bucket{Name: "uploads"},
+					Object: lambdaEvents.S3Object{Key: "second.txt"},
+				},
+			},
+		},
+	}
 	exampleDDB = lambdaEvents.DynamoDBEvent{
 		Records: []lambdaEvents.DynamoDBEventRecord{
 			lambdaEvents.DynamoDBEventRecord{
@@ -80,6 +202,104 @@ var (
 			},
 		},
 	}
+	exampleScheduledEvent = lambdaEvents.CloudWatchEvent{
+		Version:    "0",
+		ID:         "scheduled-event-id",
+		DetailType: "Scheduled Event",
+		Source:     "aws.events",
+		AccountID:  "123456789012",
+		Region:     "us-east-1",
+		Resources:  []string{"arn:aws:events:us-east-1:123456789012:rule/nightly-cron"},
+		Detail:     json.RawMessage("{}"),
+	}
+	exampleEventBridgeEvent = lambdaEvents.CloudWatchEvent{
+		Version:    "0",
+		ID:         "custom-event-id",
+		DetailType: "OrderCreated",
+		Source:     "com.example.orders",
+		AccountID:  "123456789012",
+		Region:     "eu-west-1",
+		Resources:  []string{},
+		Detail:     json.RawMessage(`{"order_id":"42"}`),
+	}
+	exampleFirehose = lambdaEvents.KinesisFirehoseEvent{
+		InvocationID:      "firehose-invocation-id",
+		DeliveryStreamArn: "arn:aws:firehose:us-east-1:123456789012:deliverystream/clicks",
+		Region:            "us-east-1",
+		Records: []lambdaEvents.KinesisFirehoseEventRecord{
+			{RecordID: "record-1", Data: []byte("first")},
+			{RecordID: "record-2", Data: []byte("second")},
+		},
+	}
+	exampleCognito = lambdaEvents.CognitoEventUserPoolsPreSignup{
+		CognitoEventUserPoolsHeader: lambdaEvents.CognitoEventUserPoolsHeader{
+			Version:       "1",
+			TriggerSource: "PreSignUp_SignUp",
+			Region:        "us-east-1",
+			UserPoolID:    "us-east-1_example",
+			UserName:      "test-user",
+			CallerContext: lambdaEvents.CognitoEventUserPoolsCallerContext{
+				ClientID: "client-id",
+			},
+		},
+	}
+	exampleMSK = `{
+		"eventSource": "aws:kafka",
+		"eventSourceArn": "arn:aws:kafka:us-east-1:123456789012:cluster/orders-cluster/abcd-1234",
+		"bootstrapServers": "b-1.orders:9092,b-2.orders:9092",
+		"records": {
+			"orders-1": [
+				{"topic": "orders", "partition": 1, "offset": 20, "value": "c2Vjb25k"}
+			],
+			"orders-0": [
+				{"topic": "orders", "partition": 0, "offset": 15, "value": "Zmlyc3Q="},
+				{"topic": "orders", "partition": 0, "offset": 16, "value": "dGhpcmQ="}
+			]
+		}
+	}`
+	exampleActiveMQ = `{
+		"eventSource": "aws:mq",
+		"eventSourceArn": "arn:aws:mq:us-east-1:123456789012:broker:orders-broker:b-1234",
+		"messages": [
+			{"messageID": "ID:message-1", "messageType": "jms/text-message", "data": "Zmlyc3Q=", "destination": {"physicalname": "orders"}},
+			{"messageID": "ID:message-2", "messageType": "jms/text-message", "data": "c2Vjb25k", "destination": {"physicalname": "orders"}}
+		]
+	}`
+	exampleRabbitMQ = `{
+		"eventSource": "aws:rmq",
+		"eventSourceArn": "arn:aws:mq:us-east-1:123456789012:broker:rabbit-broker:b-5678",
+		"rmqMessagesByQueue": {
+			"refunds::/": [{"basicProperties": {"messageId": "refund-1"}, "data": "Zmlyc3Q="}],
+			"orders::/": [{"basicProperties": {"messageId": "order-1"}, "data": "c2Vjb25k"}]
+		}
+	}`
+	exampleAppSync = `{
+		"arguments": {"id": "1"},
+		"identity": {"username": "test-user"},
+		"source": {"authorId": "2"},
+		"request": {"headers": {"host": "abcd.appsync-api.us-east-1.amazonaws.com", "x-amzn-requestid": "appsync-request-id"}},
+		"prev": null,
+		"info": {
+			"fieldName": "getPost",
+			"parentTypeName": "Query",
+			"variables": {},
+			"selectionSetList": ["id", "title"],
+			"selectionSetGraphQL": "{ id title }"
+		},
+		"stash": {}
+	}`
+	exampleStepFunctionsContext = `{
+		"Execution": {
+			"Id": "arn:aws:states:us-east-1:123456789012:execution:orders-flow:run-1",
+			"Name": "run-1",
+			"StartTime": "2020-01-01T00:00:00.000Z"
+		},
+		"StateMachine": {
+			"Id": "arn:aws:states:us-east-1:123456789012:stateMachine:orders-flow",
+			"Name": "orders-flow"
+		},
+		"State": {"Name": "ChargeCard", "EnteredTime": "2020-01-01T00:00:01.000Z", "RetryCount": 1}
+	}`
 	exampleInventedEvent = inventedEvent{
 		Name:        "Erez Freiberger",
 		Job:         "Software Engineer",
@@ -87,6 +307,16 @@ var (
 	}
 )
 
+func encodeCloudwatchLogsData(data lambdaEvents.CloudwatchLogsData) string {
+	dataJSON, err := json.Marshal(data)
+	Expect(err).To(BeNil())
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	writer.Write(dataJSON)
+	writer.Close()
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
 func verifyLabelValue(key string, value string, labelsMap map[string]string) {
 	labelValue, ok := labelsMap[key]
 	Expect(ok).To(BeTrue())
@@ -129,6 +359,139 @@ var _ = Describe("epsagon trigger suite", func() {
 				Expect(events[0].Resource.Type).To(Equal("api_gateway"))
 			})
 		})
+		Context("Handling of known trigger - API Gateway WebSocket", func() {
+			It("Identifies a WebSocket message", func() {
+				exampleJSON, err := json.Marshal(exampleWebsocketMessage)
+				if err != nil {
+					Fail("Failed to marshal json")
+				}
+				addLambdaTrigger(json.RawMessage(exampleJSON), false, triggerFactories, tracer.GlobalTracer)
+				Expect(len(events)).To(BeNumerically("==", 1))
+				Expect(events[0].Id).To(Equal("websocket-request-id"))
+				Expect(events[0].Resource.Type).To(Equal("api_gateway_websocket"))
+				Expect(events[0].Resource.Name).To(Equal("websocket-api.execute-api.us-east-1.amazonaws.com"))
+				Expect(events[0].Resource.Operation).To(Equal("MESSAGE"))
+				verifyLabelValue("connection_id", "connection-id=", events[0].Resource.Metadata)
+				verifyLabelValue("route_key", "send", events[0].Resource.Metadata)
+				verifyLabelValue("event_type", "MESSAGE", events[0].Resource.Metadata)
+				verifyLabelValue("message_id", "message-id", events[0].Resource.Metadata)
+				verifyLabelValue("stage", "prod", events[0].Resource.Metadata)
+				verifyLabelValue("body", "{\"action\":\"send\"}", events[0].Resource.Metadata)
+			})
+
+			It("Identifies a WebSocket connection", func() {
+				connectEvent := exampleWebsocketMessage
+				connectEvent.RequestContext.EventType = "CONNECT"
+				connectEvent.RequestContext.RouteKey = "$connect"
+				connectEvent.RequestContext.MessageID = nil
+				connectEvent.HTTPMethod = "GET"
+				exampleJSON, err := json.Marshal(connectEvent)
+				if err != nil {
+					Fail("Failed to marshal json")
+				}
+				addLambdaTrigger(json.RawMessage(exampleJSON), true, triggerFactories, tracer.GlobalTracer)
+				Expect(len(events)).To(BeNumerically("==", 1))
+				Expect(events[0].Resource.Type).To(Equal("api_gateway_websocket"))
+				Expect(events[0].Resource.Operation).To(Equal("CONNECT"))
+				verifyLabelValue("route_key", "$connect", events[0].Resource.Metadata)
+				Expect(events[0].Resource.Metadata).NotTo(HaveKey("message_id"))
+				Expect(events[0].Resource.Metadata).NotTo(HaveKey("body"))
+			})
+		})
+		Context("Handling of known trigger - Lambda Function URL", func() {
+			It("Tells a function URL apart from API Gateway", func() {
+				exampleJSON, err := json.Marshal(exampleFunctionURL)
+				if err != nil {
+					Fail("Failed to marshal json")
+				}
+				addLambdaTrigger(json.RawMessage(exampleJSON), false, triggerFactories, tracer.GlobalTracer)
+				Expect(len(events)).To(BeNumerically("==", 1))
+				Expect(events[0].Id).To(Equal("function-url-request-id"))
+				Expect(events[0].Resource.Type).To(Equal("lambda_function_url"))
+				Expect(events[0].Resource.Name).To(Equal("abcdefg.lambda-url.us-east-1.on.aws"))
+				Expect(events[0].Resource.Operation).To(Equal("GET"))
+				verifyLabelValue("path", "/hello", events[0].Resource.Metadata)
+				verifyLabelValue("query_string_parameters", "{\"hello\":\"world\"}", events[0].Resource.Metadata)
+				verifyLabelValue("source_ip", "127.0.0.1", events[0].Resource.Metadata)
+				verifyLabelValue("body", "hello world", events[0].Resource.Metadata)
+			})
+
+			It("Does not record headers and body in metadata only mode", func() {
+				exampleJSON, err := json.Marshal(exampleFunctionURL)
+				if err != nil {
+					Fail("Failed to marshal json")
+				}
+				addLambdaTrigger(json.RawMessage(exampleJSON), true, triggerFactories, tracer.GlobalTracer)
+				Expect(len(events)).To(BeNumerically("==", 1))
+				Expect(events[0].Resource.Metadata).NotTo(HaveKey("headers"))
+				Expect(events[0].Resource.Metadata).NotTo(HaveKey("body"))
+			})
+		})
+		Context("Handling of known trigger - API Gateway without proxy", func() {
+			It("Creates an API Gateway trigger from the mapping template fields", func() {
+				addLambdaTrigger(json.RawMessage(exampleAPIGatewayNoProxy), false, triggerFactories, tracer.GlobalTracer)
+				Expect(len(events)).To(BeNumerically("==", 1))
+				Expect(events[0].Id).To(Equal("no-proxy-request-id"))
+				Expect(events[0].Resource.Type).To(Equal("api_gateway"))
+				Expect(events[0].Resource.Name).To(Equal("api.example.com"))
+				Expect(events[0].Resource.Operation).To(Equal("POST"))
+				verifyLabelValue("stage", "prod", events[0].Resource.Metadata)
+				verifyLabelValue("path", "/items/{id}", events[0].Resource.Metadata)
+				verifyLabelValue("path_parameters", "{\"id\":\"1\"}", events[0].Resource.Metadata)
+				verifyLabelValue("query_string_parameters", "{\"verbose\":\"true\"}", events[0].Resource.Metadata)
+				Expect(events[0].Resource.Metadata["body"]).To(ContainSubstring("hello"))
+				Expect(events[0].Resource.Metadata["headers"]).To(ContainSubstring("User-Agent"))
+			})
+
+			It("Does not record headers and body in metadata only mode", func() {
+				addLambdaTrigger(json.RawMessage(exampleAPIGatewayNoProxy), true, triggerFactories, tracer.GlobalTracer)
+				Expect(len(events)).To(BeNumerically("==", 1))
+				Expect(events[0].Resource.Metadata).NotTo(HaveKey("headers"))
+				Expect(events[0].Resource.Metadata).NotTo(HaveKey("body"))
+			})
+		})
+		Context("Handling of known trigger - Application Load Balancer", func() {
+			It("Identifies an ALB request instead of API Gateway", func() {
+				exampleJSON, err := json.Marshal(exampleALB)
+				if err != nil {
+					Fail("Failed to marshal json")
+				}
+				addLambdaTrigger(json.RawMessage(exampleJSON), false, triggerFactories, tracer.GlobalTracer)
+				Expect(len(events)).To(BeNumerically("==", 1))
+				Expect(events[0].Id).To(Equal("Root=1-5bdb40ca-556d8b0c50dc66f0511bf520"))
+				Expect(events[0].Resource.Type).To(Equal("elb"))
+				Expect(events[0].Resource.Name).To(Equal("my-targets"))
+				Expect(events[0].Resource.Operation).To(Equal("POST"))
+				verifyLabelValue("target_group_arn", exampleALB.RequestContext.ELB.TargetGroupArn, events[0].Resource.Metadata)
+				verifyLabelValue("path", "/orders", events[0].Resource.Metadata)
+				verifyLabelValue("query_string_parameters", "{\"hello\":\"world\"}", events[0].Resource.Metadata)
+				verifyLabelValue("body", "{\"order\":1}", events[0].Resource.Metadata)
+				Expect(events[0].Resource.Metadata["headers"]).To(ContainSubstring("my-alb.example.com"))
+			})
+
+			It("Does not record headers and body in metadata only mode", func() {
+				exampleJSON, err := json.Marshal(exampleALB)
+				if err != nil {
+					Fail("Failed to marshal json")
+				}
+				addLambdaTrigger(json.RawMessage(exampleJSON), true, triggerFactories, tracer.GlobalTracer)
+				Expect(len(events)).To(BeNumerically("==", 1))
+				Expect(events[0].Resource.Metadata).NotTo(HaveKey("headers"))
+				Expect(events[0].Resource.Metadata).NotTo(HaveKey("body"))
+			})
+
+			It("Records the response status code", func() {
+				exampleJSON, err := json.Marshal(exampleALB)
+				if err != nil {
+					Fail("Failed to marshal json")
+				}
+				triggerEvent := addLambdaTrigger(json.RawMessage(exampleJSON), true, triggerFactories, tracer.GlobalTracer)
+				addTriggerResponseData(triggerEvent, &lambdaEvents.ALBTargetGroupResponse{StatusCode: 201})
+				verifyLabelValue("status_code", "201", events[0].Resource.Metadata)
+				addTriggerResponseData(triggerEvent, lambdaEvents.ALBTargetGroupResponse{StatusCode: 502})
+				verifyLabelValue("status_code", "502", events[0].Resource.Metadata)
+			})
+		})
 		Context("Handling of known trigger - DynamoDB", func() {
 			It("Identifies the first known handler, DynamoDB", func() {
 				exampleJSON, err := json.Marshal(exampleDDB)
@@ -151,6 +514,363 @@ var _ = Describe("epsagon trigger suite", func() {
 				)
 			})
 		})
+		Context("Handling of known trigger - EventBridge", func() {
+			It("Identifies a scheduled event", func() {
+				exampleJSON, err := json.Marshal(exampleScheduledEvent)
+				if err != nil {
+					Fail("Failed to marshal json")
+				}
+				addLambdaTrigger(json.RawMessage(exampleJSON), false, triggerFactories, tracer.GlobalTracer)
+				Expect(len(events)).To(BeNumerically("==", 1))
+				Expect(events[0].Id).To(Equal("scheduled-event-id"))
+				Expect(events[0].Resource.Type).To(Equal("events"))
+				Expect(events[0].Resource.Name).To(Equal("nightly-cron"))
+				Expect(events[0].Resource.Operation).To(Equal("Scheduled Event"))
+				verifyLabelValue("rule", "nightly-cron", events[0].Resource.Metadata)
+				verifyLabelValue("source", "aws.events", events[0].Resource.Metadata)
+				verifyLabelValue("detail_type", "Scheduled Event", events[0].Resource.Metadata)
+				verifyLabelValue("account", "123456789012", events[0].Resource.Metadata)
+				verifyLabelValue("region", "us-east-1", events[0].Resource.Metadata)
+				verifyLabelValue("event_id", "scheduled-event-id", events[0].Resource.Metadata)
+				verifyLabelValue("detail", "{}", events[0].Resource.Metadata)
+			})
+
+			It("Identifies an event with a custom source", func() {
+				exampleJSON, err := json.Marshal(exampleEventBridgeEvent)
+				if err != nil {
+					Fail("Failed to marshal json")
+				}
+				addLambdaTrigger(json.RawMessage(exampleJSON), false, triggerFactories, tracer.GlobalTracer)
+				Expect(len(events)).To(BeNumerically("==", 1))
+				Expect(events[0].Resource.Type).To(Equal("events"))
+				Expect(events[0].Resource.Name).To(Equal("com.example.orders"))
+				Expect(events[0].Resource.Operation).To(Equal("OrderCreated"))
+				verifyLabelValue("detail", `{"order_id":"42"}`, events[0].Resource.Metadata)
+			})
+
+			It("Does not record the detail in metadata only mode", func() {
+				exampleJSON, err := json.Marshal(exampleEventBridgeEvent)
+				if err != nil {
+					Fail("Failed to marshal json")
+				}
+				addLambdaTrigger(json.RawMessage(exampleJSON), true, triggerFactories, tracer.GlobalTracer)
+				Expect(len(events)).To(BeNumerically("==", 1))
+				Expect(events[0].Resource.Metadata).NotTo(HaveKey("detail"))
+				verifyLabelValue("region", "eu-west-1", events[0].Resource.Metadata)
+			})
+		})
+		Context("Handling of known trigger - CloudWatch Logs", func() {
+			var exampleJSON []byte
+
+			BeforeEach(func() {
+				var err error
+				exampleJSON, err = json.Marshal(lambdaEvents.CloudwatchLogsEvent{
+					AWSLogs: lambdaEvents.CloudwatchLogsRawData{
+						Data: encodeCloudwatchLogsData(lambdaEvents.CloudwatchLogsData{
+							Owner:               "123456789012",
+							LogGroup:            "/aws/lambda/producer",
+							LogStream:           "2020/01/01/[$LATEST]abc",
+							SubscriptionFilters: []string{"errors"},
+							MessageType:         "DATA_MESSAGE",
+							LogEvents: []lambdaEvents.CloudwatchLogsLogEvent{
+								{ID: "log-event-1", Message: "first"},
+								{ID: "log-event-2", Message: "second"},
+							},
+						}),
+					},
+				})
+				Expect(err).To(BeNil())
+			})
+
+			It("Decodes the subscription data", func() {
+				addLambdaTrigger(json.RawMessage(exampleJSON), false, triggerFactories, tracer.GlobalTracer)
+				Expect(len(events)).To(BeNumerically("==", 1))
+				Expect(events[0].Id).To(Equal("log-event-1"))
+				Expect(events[0].Resource.Type).To(Equal("cloudwatch_logs"))
+				Expect(events[0].Resource.Name).To(Equal("/aws/lambda/producer"))
+				Expect(events[0].Resource.Operation).To(Equal("DATA_MESSAGE"))
+				verifyLabelValue("log_group", "/aws/lambda/producer", events[0].Resource.Metadata)
+				verifyLabelValue("log_stream", "2020/01/01/[$LATEST]abc", events[0].Resource.Metadata)
+				verifyLabelValue("log_events_count", "2", events[0].Resource.Metadata)
+				verifyLabelValue("subscription_filters", "errors", events[0].Resource.Metadata)
+				verifyLabelValue("log_events", `["first","second"]`, events[0].Resource.Metadata)
+			})
+
+			It("Does not record the log events in metadata only mode", func() {
+				addLambdaTrigger(json.RawMessage(exampleJSON), true, triggerFactories, tracer.GlobalTracer)
+				Expect(len(events)).To(BeNumerically("==", 1))
+				Expect(events[0].Resource.Metadata).NotTo(HaveKey("log_events"))
+			})
+
+			It("Adds an exception for invalid data", func() {
+				addLambdaTrigger(json.RawMessage(`{"awslogs":{"data":"invalid"}}`), false, triggerFactories, tracer.GlobalTracer)
+				Expect(events).To(BeEmpty())
+				Expect(exceptions).To(HaveLen(1))
+				Expect(exceptions[0].Type).To(Equal("trigger-creation"))
+			})
+		})
+		Context("Handling of known trigger - Kinesis Firehose", func() {
+			It("Identifies a transformation event", func() {
+				exampleJSON, err := json.Marshal(exampleFirehose)
+				if err != nil {
+					Fail("Failed to marshal json")
+				}
+				addLambdaTrigger(json.RawMessage(exampleJSON), false, triggerFactories, tracer.GlobalTracer)
+				Expect(len(events)).To(BeNumerically("==", 1))
+				Expect(events[0].Id).To(Equal("firehose-invocation-id"))
+				Expect(events[0].Resource.Type).To(Equal("firehose"))
+				Expect(events[0].Resource.Name).To(Equal("clicks"))
+				verifyLabelValue("delivery_stream_arn", exampleFirehose.DeliveryStreamArn, events[0].Resource.Metadata)
+				verifyLabelValue("record_count", "2", events[0].Resource.Metadata)
+				verifyLabelValue("region", "us-east-1", events[0].Resource.Metadata)
+			})
+		})
+		Context("Handling of known trigger - Cognito", func() {
+			It("Identifies a user pool trigger", func() {
+				exampleJSON, err := json.Marshal(exampleCognito)
+				if err != nil {
+					Fail("Failed to marshal json")
+				}
+				addLambdaTrigger(json.RawMessage(exampleJSON), false, triggerFactories, tracer.GlobalTracer)
+				Expect(len(events)).To(BeNumerically("==", 1))
+				Expect(events[0].Resource.Type).To(Equal("cognito"))
+				Expect(events[0].Resource.Name).To(Equal("us-east-1_example"))
+				Expect(events[0].Resource.Operation).To(Equal("PreSignUp_SignUp"))
+				verifyLabelValue("trigger_source", "PreSignUp_SignUp", events[0].Resource.Metadata)
+				verifyLabelValue("user_pool_id", "us-east-1_example", events[0].Resource.Metadata)
+				verifyLabelValue("user_name", "test-user", events[0].Resource.Metadata)
+				verifyLabelValue("client_id", "client-id", events[0].Resource.Metadata)
+			})
+		})
+		Context("Handling of known trigger - Kafka", func() {
+			It("Identifies an MSK event", func() {
+				addLambdaTrigger(json.RawMessage(exampleMSK), false, triggerFactories, tracer.GlobalTracer)
+				Expect(exceptions).To(BeEmpty())
+				Expect(len(events)).To(BeNumerically("==", 1))
+				Expect(events[0].Resource.Type).To(Equal("kafka"))
+				Expect(events[0].Resource.Name).To(Equal("orders"))
+				verifyLabelValue("event_source", "aws:kafka", events[0].Resource.Metadata)
+				verifyLabelValue("cluster", "orders-cluster", events[0].Resource.Metadata)
+				verifyLabelValue("topics", "orders", events[0].Resource.Metadata)
+				verifyLabelValue("partitions", "orders-0,orders-1", events[0].Resource.Metadata)
+				verifyLabelValue("offsets", `{"orders-0":[15,16],"orders-1":[20]}`, events[0].Resource.Metadata)
+				verifyLabelValue("message_count", "3", events[0].Resource.Metadata)
+			})
+
+			It("Identifies a self-managed Kafka event", func() {
+				selfManaged := strings.Replace(exampleMSK, `"aws:kafka"`, `"SelfManagedKafka"`, 1)
+				selfManaged = strings.Replace(
+					selfManaged, `"arn:aws:kafka:us-east-1:123456789012:cluster/orders-cluster/abcd-1234"`, `""`, 1)
+				addLambdaTrigger(json.RawMessage(selfManaged), false, triggerFactories, tracer.GlobalTracer)
+				Expect(len(events)).To(BeNumerically("==", 1))
+				Expect(events[0].Resource.Type).To(Equal("kafka"))
+				verifyLabelValue("cluster", "b-1.orders:9092,b-2.orders:9092", events[0].Resource.Metadata)
+			})
+		})
+		Context("Handling of known trigger - Amazon MQ", func() {
+			It("Identifies an ActiveMQ event", func() {
+				addLambdaTrigger(json.RawMessage(exampleActiveMQ), false, triggerFactories, tracer.GlobalTracer)
+				Expect(len(events)).To(BeNumerically("==", 1))
+				Expect(events[0].Id).To(Equal("ID:message-1"))
+				Expect(events[0].Resource.Type).To(Equal("amazon_mq"))
+				Expect(events[0].Resource.Name).To(Equal("orders"))
+				verifyLabelValue("broker", "orders-broker", events[0].Resource.Metadata)
+				verifyLabelValue("engine", "activemq", events[0].Resource.Metadata)
+				verifyLabelValue("message_count", "2", events[0].Resource.Metadata)
+				verifyLabelValue("message_ids", `["ID:message-1","ID:message-2"]`, events[0].Resource.Metadata)
+			})
+
+			It("Identifies a RabbitMQ event", func() {
+				addLambdaTrigger(json.RawMessage(exampleRabbitMQ), false, triggerFactories, tracer.GlobalTracer)
+				Expect(len(events)).To(BeNumerically("==", 1))
+				Expect(events[0].Resource.Type).To(Equal("amazon_mq"))
+				verifyLabelValue("broker", "rabbit-broker", events[0].Resource.Metadata)
+				verifyLabelValue("engine", "rabbitmq", events[0].Resource.Metadata)
+				verifyLabelValue("queues", "orders::/,refunds::/", events[0].Resource.Metadata)
+				verifyLabelValue("message_ids", `["order-1","refund-1"]`, events[0].Resource.Metadata)
+			})
+		})
+		Context("Handling of known trigger - AppSync", func() {
+			It("Identifies a resolver invocation", func() {
+				addLambdaTrigger(json.RawMessage(exampleAppSync), false, triggerFactories, tracer.GlobalTracer)
+				Expect(exceptions).To(BeEmpty())
+				Expect(len(events)).To(BeNumerically("==", 1))
+				Expect(events[0].Id).To(Equal("appsync-request-id"))
+				Expect(events[0].Resource.Type).To(Equal("appsync"))
+				Expect(events[0].Resource.Name).To(Equal("abcd.appsync-api.us-east-1.amazonaws.com"))
+				Expect(events[0].Resource.Operation).To(Equal("getPost"))
+				verifyLabelValue("parent_type_name", "Query", events[0].Resource.Metadata)
+				verifyLabelValue("field_name", "getPost", events[0].Resource.Metadata)
+				verifyLabelValue("selection_set", "{ id title }", events[0].Resource.Metadata)
+				verifyLabelValue("arguments", `{"id": "1"}`, events[0].Resource.Metadata)
+				verifyLabelValue("identity", `{"username": "test-user"}`, events[0].Resource.Metadata)
+			})
+
+			It("Does not record arguments and identity in metadata only mode", func() {
+				addLambdaTrigger(json.RawMessage(exampleAppSync), true, triggerFactories, tracer.GlobalTracer)
+				Expect(len(events)).To(BeNumerically("==", 1))
+				Expect(events[0].Resource.Metadata).NotTo(HaveKey("arguments"))
+				Expect(events[0].Resource.Metadata).NotTo(HaveKey("identity"))
+			})
+		})
+		Context("Handling of known trigger - Step Functions", func() {
+			verifyStepFunctionsEvent := func() {
+				Expect(exceptions).To(BeEmpty())
+				Expect(len(events)).To(BeNumerically("==", 1))
+				Expect(events[0].Id).To(Equal("arn:aws:states:us-east-1:123456789012:execution:orders-flow:run-1"))
+				Expect(events[0].Resource.Type).To(Equal("step_function"))
+				Expect(events[0].Resource.Name).To(Equal("orders-flow"))
+				Expect(events[0].Resource.Operation).To(Equal("ChargeCard"))
+				verifyLabelValue("execution_name", "run-1", events[0].Resource.Metadata)
+				verifyLabelValue(
+					"state_machine_arn",
+					"arn:aws:states:us-east-1:123456789012:stateMachine:orders-flow",
+					events[0].Resource.Metadata,
+				)
+				verifyLabelValue("retry_count", "1", events[0].Resource.Metadata)
+			}
+
+			It("Identifies the context object passed as the input", func() {
+				addLambdaTrigger(json.RawMessage(exampleStepFunctionsContext), false, triggerFactories, tracer.GlobalTracer)
+				verifyStepFunctionsEvent()
+			})
+
+			It("Identifies the context object passed in the input context", func() {
+				input := `{"order": {"id": 1}, "context": ` + exampleStepFunctionsContext + `}`
+				addLambdaTrigger(json.RawMessage(input), false, triggerFactories, tracer.GlobalTracer)
+				verifyStepFunctionsEvent()
+			})
+
+			It("Adds a JSON event for task input without context", func() {
+				addLambdaTrigger(json.RawMessage(`{"order": {"id": 1}}`), false, triggerFactories, tracer.GlobalTracer)
+				Expect(len(events)).To(BeNumerically("==", 1))
+				Expect(events[0].Resource.Type).To(Equal("json"))
+			})
+		})
+		Context("Handling of batched triggers", func() {
+			It("Records every SQS message of the batch", func() {
+				exampleJSON, err := json.Marshal(exampleSQSBatch)
+				if err != nil {
+					Fail("Failed to marshal json")
+				}
+				addLambdaTrigger(json.RawMessage(exampleJSON), true, triggerFactories, tracer.GlobalTracer)
+				Expect(len(events)).To(BeNumerically("==", 1))
+				Expect(events[0].Id).To(Equal("message-1"))
+				Expect(events[0].Resource.Name).To(Equal("orders"))
+				verifyLabelValue("batch_size", "3", events[0].Resource.Metadata)
+				verifyLabelValue("message_ids", `["message-1","message-2","message-3"]`, events[0].Resource.Metadata)
+				verifyLabelValue("sources", `["orders","refunds"]`, events[0].Resource.Metadata)
+				var records []map[string]string
+				Expect(json.Unmarshal([]byte(events[0].Resource.Metadata["records"]), &records)).To(Succeed())
+				Expect(records).To(HaveLen(3))
+				Expect(records[1]).To(Equal(map[string]string{
+					"message_id":                "message-2",
+					"approximate_receive_count": "2",
+				}))
+			})
+
+			It("Caps the number of summarized records", func() {
+				batch := lambdaEvents.SQSEvent{}
+				for i := 0; i < maxSummarizedRecords+10; i++ {
+					batch.Records = append(batch.Records, lambdaEvents.SQSMessage{
+						MessageId:      fmt.Sprintf("message-%d", i),
+						EventSource:    "aws:sqs",
+						EventSourceARN: "arn:aws:sqs:us-east-1:123456789012:orders",
+					})
+				}
+				exampleJSON, err := json.Marshal(batch)
+				if err != nil {
+					Fail("Failed to marshal json")
+				}
+				addLambdaTrigger(json.RawMessage(exampleJSON), true, triggerFactories, tracer.GlobalTracer)
+				Expect(len(events)).To(BeNumerically("==", 1))
+				verifyLabelValue("batch_size", strconv.Itoa(maxSummarizedRecords+10), events[0].Resource.Metadata)
+				var records []map[string]string
+				Expect(json.Unmarshal([]byte(events[0].Resource.Metadata["records"]), &records)).To(Succeed())
+				Expect(records).To(HaveLen(maxSummarizedRecords))
+				Expect(records[0]["message_id"]).To(Equal("message-0"))
+				var messageIDs []string
+				Expect(json.Unmarshal([]byte(events[0].Resource.Metadata["message_ids"]), &messageIDs)).To(Succeed())
+				Expect(messageIDs).To(HaveLen(maxSummarizedRecords + 10))
+			})
+
+			It("Records every S3 object of the batch", func() {
+				exampleJSON, err := json.Marshal(exampleS3Batch)
+				if err != nil {
+					Fail("Failed to marshal json")
+				}
+				addLambdaTrigger(json.RawMessage(exampleJSON), false, triggerFactories, tracer.GlobalTracer)
+				Expect(len(events)).To(BeNumerically("==", 1))
+				verifyLabelValue("batch_size", "2", events[0].Resource.Metadata)
+				verifyLabelValue(
+					"records",
+					`[{"event_name":"ObjectCreated:Put","object_key":"first.txt"},{"event_name":"ObjectRemoved:Delete","object_key":"second.txt"}]`,
+					events[0].Resource.Metadata,
+				)
+				Expect(events[0].Resource.Metadata).NotTo(HaveKey("sources"))
+			})
+
+			It("Records the batch size of a single record", func() {
+				exampleJSON, err := json.Marshal(exampleDDB)
+				if err != nil {
+					Fail("Failed to marshal json")
+				}
+				addLambdaTrigger(json.RawMessage(exampleJSON), false, triggerFactories, tracer.GlobalTracer)
+				Expect(len(events)).To(BeNumerically("==", 1))
+				verifyLabelValue("batch_size", "1", events[0].Resource.Metadata)
+				Expect(events[0].Resource.Metadata).NotTo(HaveKey("sources"))
+			})
+		})
+		Context("Handling of JSON payloads with detection field names", func() {
+			verifyJSONTrigger := func(payload string) {
+				addLambdaTrigger(json.RawMessage(payload), false, triggerFactories, tracer.GlobalTracer)
+				Expect(len(events)).To(BeNumerically("==", 1))
+				Expect(events[0].Resource.Type).To(Equal("json"))
+				Expect(exceptions).To(BeEmpty())
+			}
+
+			It("Adds a JSON event for an info field that is not an AppSync info", func() {
+				verifyJSONTrigger(`{"info": "hello"}`)
+			})
+
+			It("Adds a JSON event for an info object with other field types", func() {
+				verifyJSONTrigger(`{"info": {"fieldName": 1, "parentTypeName": ["Query"]}}`)
+			})
+
+			It("Adds a JSON event for an execution field that is not an object", func() {
+				verifyJSONTrigger(`{"execution": "x"}`)
+			})
+
+			It("Adds a JSON event for an execution object with another ID type", func() {
+				verifyJSONTrigger(`{"execution": {"id": 5}}`)
+			})
+
+			It("Adds a JSON event for an awslogs field that is not an object", func() {
+				verifyJSONTrigger(`{"awslogs": "x"}`)
+			})
+
+			It("Adds a JSON event for Cognito and Firehose field names of other types", func() {
+				verifyJSONTrigger(`{"triggerSource": 1, "userPoolId": true, "deliveryStreamArn": {}}`)
+			})
+
+			It("Adds a JSON event for a detail-type field that is not a string", func() {
+				verifyJSONTrigger(`{"detail-type": {}}`)
+			})
+
+			It("Adds a JSON event for an eventSource field that is not a string", func() {
+				verifyJSONTrigger(`{"eventSource": 5}`)
+			})
+
+			It("Adds a JSON event for a request context elb field that is not an object", func() {
+				verifyJSONTrigger(`{"requestContext": {"elb": "x"}}`)
+				events = events[:0]
+				verifyJSONTrigger(`{"requestContext": {"elb": {"targetGroupArn": 1}}}`)
+			})
+
+			It("Adds a JSON event for request context connection fields of other types", func() {
+				verifyJSONTrigger(`{"requestContext": {"connectionId": 1, "domainName": ["x"]}}`)
+			})
+		})
 		Context("Handling of known trigger with extra fields", func() {
 			It("Identifies the first known handler", func() {
 				exampleJSON, err := json.Marshal(exampleAPIGateWay)
@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"runtime/debug"
 	"strconv"
 	"strings"
 	"time"
@@ -37,6 +36,8 @@ type preInvokeData struct {
 	InvocationMetadata map[string]string
 	LambdaContext      *lambdacontext.LambdaContext
 	StartTime          float64
+	RuntimeStats       *tracer.RuntimeStats
+	TriggerEvent       *protocol.Event
 }
 
 type invocationData struct {
@@ -47,6 +48,15 @@ type invocationData struct {
 	thrownError   interface{}
 }
 
+// tracerConfig returns the config of the wrapper tracer, which includes the
+// overrides applied when the tracer was created
+func (wrapper *epsagonLambdaWrapper) tracerConfig() *tracer.Config {
+	if config := wrapper.tracer.GetConfig(); config != nil {
+		return config
+	}
+	return &wrapper.config.Config
+}
+
 func getAWSAccount(lc *lambdacontext.LambdaContext) string {
 	arnParts := strings.Split(lc.InvokedFunctionArn, ":")
 	if len(arnParts) >= 4 {
@@ -59,7 +69,7 @@ func createLambdaEvent(preInvokeInfo *preInvokeData) *protocol.Event {
 	endTime := tracer.GetTimestamp()
 	duration := endTime - preInvokeInfo.StartTime
 
-	return &protocol.Event{
+	lambdaEvent := &protocol.Event{
 		Id:        preInvokeInfo.LambdaContext.AwsRequestID,
 		StartTime: preInvokeInfo.StartTime,
 		Resource: &protocol.Resource{
@@ -71,6 +81,10 @@ func createLambdaEvent(preInvokeInfo *preInvokeData) *protocol.Event {
 		Origin:   "runner",
 		Duration: duration,
 	}
+	if preInvokeInfo.RuntimeStats != nil {
+		preInvokeInfo.RuntimeStats.AddRuntimeMetrics(lambdaEvent)
+	}
+	return lambdaEvent
 }
 
 func (wrapper *epsagonLambdaWrapper) preInvokeOps(
@@ -104,13 +118,18 @@ func (wrapper *epsagonLambdaWrapper) preInvokeOps(
 	}
 	coldStart = false
 
-	addLambdaTrigger(payload, wrapper.config.MetadataOnly, triggerFactories, wrapper.tracer)
+	triggerEvent := addLambdaTrigger(payload, wrapper.tracerConfig().MetadataOnly, triggerFactories, wrapper.tracer)
 
-	return &preInvokeData{
+	info = &preInvokeData{
 		InvocationMetadata: metadata,
 		LambdaContext:      lc,
 		StartTime:          startTime,
+		TriggerEvent:       triggerEvent,
+	}
+	if wrapper.tracerConfig().RuntimeMetrics {
+		info.RuntimeStats = tracer.CaptureRuntimeStats()
 	}
+	return info
 }
 
 func (wrapper *epsagonLambdaWrapper) postInvokeOps(
@@ -125,8 +144,10 @@ func (wrapper *epsagonLambdaWrapper) postInvokeOps(
 	lambdaEvent := createLambdaEvent(preInvokeInfo)
 	lambdaEvent.ErrorCode = invokeInfo.errorStatus
 	lambdaEvent.Exception = invokeInfo.ExceptionInfo
+	addTriggerResponseData(preInvokeInfo.TriggerEvent, invokeInfo.result)
+	addBatchItemFailures(lambdaEvent, preInvokeInfo.TriggerEvent, invokeInfo.result)
 
-	if !wrapper.config.MetadataOnly {
+	if !wrapper.tracerConfig().MetadataOnly {
 		result, err := json.Marshal(invokeInfo.result)
 		if err == nil {
 			lambdaEvent.Resource.Metadata["return_value"] = string(result)
@@ -194,12 +215,8 @@ func (wrapper *epsagonLambdaWrapper) InvokeClientLambda(
 	defer func() {
 		invokeInfo.thrownError = recover()
 		if invokeInfo.thrownError != nil {
-			invokeInfo.ExceptionInfo = &protocol.Exception{
-				Type:      "Runtime Error",
-				Message:   fmt.Sprintf("%v", invokeInfo.thrownError),
-				Traceback: string(debug.Stack()),
-				Time:      tracer.GetTimestamp(),
-			}
+			invokeInfo.ExceptionInfo = tracer.NewTracerException(wrapper.tracer,
+				"Runtime Error", fmt.Sprintf("%v", invokeInfo.thrownError), invokeInfo.thrownError)
 			invokeInfo.errorStatus = protocol.ErrorCode_EXCEPTION
 		}
 	}()
@@ -218,6 +235,7 @@ func (wrapper *epsagonLambdaWrapper) InvokeClientLambda(
 			Traceback: "",
 			Time:      tracer.GetTimestamp(),
 		}
+		tracer.AddErrorDetails(invokeInfo.ExceptionInfo, err)
 	}
 	invokeInfo.result = result
 	invokeInfo.err = err
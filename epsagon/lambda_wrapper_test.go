@@ -4,16 +4,28 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"reflect"
 	"time"
 
+	lambdaEvents "github.com/aws/aws-lambda-go/events"
 	"github.com/epsagon/epsagon-go/protocol"
 	"github.com/epsagon/epsagon-go/tracer"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
 
+// sqsEventResponse mirrors events.SQSEventResponse of newer aws-lambda-go versions
+type sqsEventResponse struct {
+	BatchItemFailures []sqsBatchItemFailure `json:"batchItemFailures"`
+}
+
+type sqsBatchItemFailure struct {
+	ItemIdentifier string `json:"itemIdentifier"`
+}
+
 var _ = Describe("lambda_wrapper", func() {
 	Describe("WrapLambdaHandler", func() {
 		Context("called with nil config", func() {
@@ -84,6 +96,129 @@ var _ = Describe("lambda_wrapper", func() {
 				Expect(events).To(HaveLen(2))
 			})
 
+			It("Records the ALB response status code on the trigger", func() {
+				wrapper := &epsagonLambdaWrapper{
+					config: &Config{},
+					handler: makeGenericHandler(func() (lambdaEvents.ALBTargetGroupResponse, error) {
+						return lambdaEvents.ALBTargetGroupResponse{StatusCode: 404}, nil
+					}),
+					tracer: tracer.GlobalTracer,
+				}
+
+				payload := json.RawMessage(`{"httpMethod":"GET","path":"/","requestContext":{"elb":{"targetGroupArn":"arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/my-targets/1"}}}`)
+				wrapper.Invoke(context.Background(), payload)
+
+				Expect(exceptions).To(BeEmpty())
+				Expect(events).To(HaveLen(2))
+				Expect(events[0].Resource.Type).To(Equal("elb"))
+				Expect(events[0].Resource.Metadata["status_code"]).To(Equal("404"))
+			})
+
+			Context("Watched config file", func() {
+				var (
+					dir     string
+					watcher *tracer.ConfigWatcher
+				)
+				BeforeEach(func() {
+					var err error
+					dir, err = ioutil.TempDir("", "epsagon-config")
+					Expect(err).To(BeNil())
+				})
+				AfterEach(func() {
+					if watcher != nil {
+						watcher.Stop()
+					}
+					os.RemoveAll(dir)
+				})
+
+				It("Captures payloads when the file turns off metadata only", func() {
+					path := filepath.Join(dir, "epsagon.json")
+					Expect(ioutil.WriteFile(path, []byte(`{"metadata_only": false}`), 0644)).To(Succeed())
+					var err error
+					watcher, err = tracer.WatchConfigFile(path, time.Minute)
+					Expect(err).To(BeNil())
+
+					config := &Config{Config: tracer.Config{Token: "token", MetadataOnly: true}}
+					wrapperTracer := &tracer.MockedEpsagonTracer{
+						Events:     &events,
+						Exceptions: &exceptions,
+						Config:     tracer.CreateTracer(&config.Config).GetConfig(),
+					}
+					wrapper := &epsagonLambdaWrapper{
+						config:  config,
+						handler: makeGenericHandler(func() (string, error) { return "result", nil }),
+						tracer:  wrapperTracer,
+					}
+					payload, err := json.Marshal(exampleAPIGateWay)
+					Expect(err).To(BeNil())
+					wrapper.Invoke(context.Background(), payload)
+
+					Expect(events).To(HaveLen(2))
+					Expect(events[0].Resource.Type).To(Equal("api_gateway"))
+					Expect(events[0].Resource.Metadata).To(HaveKey("body"))
+					Expect(events[1].Resource.Metadata["return_value"]).To(Equal(`"result"`))
+				})
+			})
+
+			Context("Partial batch responses", func() {
+				invokeWithResponse := func(payload string, response interface{}, err error) *protocol.Event {
+					wrapper := &epsagonLambdaWrapper{
+						config: &Config{},
+						handler: makeGenericHandler(func() (interface{}, error) {
+							return response, err
+						}),
+						tracer: tracer.GlobalTracer,
+					}
+					wrapper.Invoke(context.Background(), json.RawMessage(payload))
+					Expect(events).To(HaveLen(2))
+					return events[1]
+				}
+				sqsPayload := `{"Records":[
+					{"messageId":"message-1","eventSource":"aws:sqs","eventSourceARN":"arn:aws:sqs:us-east-1:123456789012:orders"},
+					{"messageId":"message-2","eventSource":"aws:sqs","eventSourceARN":"arn:aws:sqs:us-east-1:123456789012:orders"},
+					{"messageId":"message-3","eventSource":"aws:sqs","eventSourceARN":"arn:aws:sqs:us-east-1:123456789012:orders"}]}`
+
+				It("Marks the runner as an error when batch items failed", func() {
+					runner := invokeWithResponse(sqsPayload, sqsEventResponse{
+						BatchItemFailures: []sqsBatchItemFailure{
+							{ItemIdentifier: "message-2"},
+							{ItemIdentifier: "message-3"},
+						},
+					}, nil)
+					Expect(runner.ErrorCode).To(Equal(protocol.ErrorCode_ERROR))
+					Expect(runner.Resource.Metadata["batch_item_failures"]).To(Equal(`["message-2","message-3"]`))
+					Expect(runner.Resource.Metadata["failed_items_count"]).To(Equal("2"))
+					Expect(runner.Exception.Type).To(Equal("Partial Batch Failure"))
+					Expect(runner.Exception.Message).To(Equal("2 of 3 batch items failed"))
+				})
+
+				It("Keeps the runner successful when no batch item failed", func() {
+					runner := invokeWithResponse(sqsPayload, sqsEventResponse{
+						BatchItemFailures: []sqsBatchItemFailure{},
+					}, nil)
+					Expect(runner.ErrorCode).To(Equal(protocol.ErrorCode_OK))
+					Expect(runner.Exception).To(BeNil())
+					Expect(runner.Resource.Metadata).NotTo(HaveKey("batch_item_failures"))
+				})
+
+				It("Keeps the handler error", func() {
+					runner := invokeWithResponse(sqsPayload, sqsEventResponse{
+						BatchItemFailures: []sqsBatchItemFailure{{ItemIdentifier: "message-1"}},
+					}, fmt.Errorf("handler failed"))
+					Expect(runner.ErrorCode).To(Equal(protocol.ErrorCode_ERROR))
+					Expect(runner.Exception.Message).To(Equal("handler failed"))
+					Expect(runner.Resource.Metadata["failed_items_count"]).To(Equal("1"))
+				})
+
+				It("Ignores batch responses of other triggers", func() {
+					runner := invokeWithResponse("{}", sqsEventResponse{
+						BatchItemFailures: []sqsBatchItemFailure{{ItemIdentifier: "message-1"}},
+					}, nil)
+					Expect(runner.ErrorCode).To(Equal(protocol.ErrorCode_OK))
+					Expect(runner.Resource.Metadata).NotTo(HaveKey("failed_items_count"))
+				})
+			})
+
 			Context("Lambda timeout handling", func() {
 				It("Marks event as success when timeout defined but not reached", func() {
 					const lambdaTimeout = 5 * time.Minute
@@ -0,0 +1,143 @@
+package epsagon
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/epsagon/epsagon-go/protocol"
+	"github.com/epsagon/epsagon-go/tracer"
+)
+
+// InterruptedErrorType is the exception type of runner events interrupted by Shutdown
+const InterruptedErrorType = "Interrupted"
+
+// DefaultShutdownTimeout is the default time given to flush traces on a termination signal
+const DefaultShutdownTimeout = 3 * time.Second
+
+var (
+	activeWrappersLock sync.Mutex
+	activeWrappers     = make(map[*GenericWrapper]struct{})
+)
+
+func registerActiveWrapper(wrapper *GenericWrapper) {
+	activeWrappersLock.Lock()
+	defer activeWrappersLock.Unlock()
+	activeWrappers[wrapper] = struct{}{}
+}
+
+func unregisterActiveWrapper(wrapper *GenericWrapper) {
+	activeWrappersLock.Lock()
+	defer activeWrappersLock.Unlock()
+	delete(activeWrappers, wrapper)
+}
+
+func popActiveWrappers() []*GenericWrapper {
+	activeWrappersLock.Lock()
+	defer activeWrappersLock.Unlock()
+	wrappers := make([]*GenericWrapper, 0, len(activeWrappers))
+	for wrapper := range activeWrappers {
+		wrappers = append(wrappers, wrapper)
+		delete(activeWrappers, wrapper)
+	}
+	return wrappers
+}
+
+// Shutdown marks the runner events of the functions that are still running as
+// interrupted, and flushes their traces and the global tracer trace.
+// It returns the context error if the traces were not sent before the context is done
+func Shutdown(ctx context.Context) error {
+	tracers := make(map[tracer.Tracer]struct{})
+	for _, wrapper := range popActiveWrappers() {
+		if wrapper.interrupt() {
+			tracers[wrapper.tracer] = struct{}{}
+		}
+	}
+	if tracer.GlobalTracer != nil {
+		tracers[tracer.GlobalTracer] = struct{}{}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var wg sync.WaitGroup
+		for currentTracer := range tracers {
+			wg.Add(1)
+			go func(currentTracer tracer.Tracer) {
+				defer wg.Done()
+				defer func() { recover() }()
+				currentTracer.Stop()
+			}(currentTracer)
+		}
+		wg.Wait()
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// FlushOnSignal calls Shutdown when the process receives SIGTERM or SIGINT,
+// giving it up to timeout to flush the traces, and then lets the signal
+// terminate the process. The returned function removes the signal hook
+func FlushOnSignal(timeout time.Duration) (stop func()) {
+	if timeout <= 0 {
+		timeout = DefaultShutdownTimeout
+	}
+	signals := make(chan os.Signal, 1)
+	cancel := make(chan struct{})
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
+	go func() {
+		select {
+		case sig := <-signals:
+			signal.Stop(signals)
+			ctx, cancelShutdown := context.WithTimeout(context.Background(), timeout)
+			defer cancelShutdown()
+			if err := Shutdown(ctx); err != nil {
+				tracer.DefaultLogger(false).Errorf("failed to flush traces on %v: %v", sig, err)
+			}
+			reraiseSignal(sig)
+		case <-cancel:
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(cancel)
+		})
+	}
+}
+
+// reraiseSignal sends the signal again now that it is no longer handled,
+// so the process terminates the way it would have without the hook
+func reraiseSignal(sig os.Signal) {
+	process, err := os.FindProcess(os.Getpid())
+	if err == nil {
+		err = process.Signal(sig)
+	}
+	if err != nil {
+		os.Exit(1)
+	}
+}
+
+// interrupt adds the runner event of a running wrapper, marked as
+// interrupted. It returns false if the runner event was already added
+func (wrapper *GenericWrapper) interrupt() bool {
+	wrapper.runnerLock.Lock()
+	defer wrapper.runnerLock.Unlock()
+	if wrapper.runnerAdded || wrapper.runner == nil {
+		return false
+	}
+	wrapper.runner.ErrorCode = protocol.ErrorCode_EXCEPTION
+	wrapper.runner.Exception = tracer.NewTracerException(wrapper.tracer,
+		InterruptedErrorType, "The process was shut down before the function returned", nil)
+	wrapper.runner.Resource.Metadata[tracer.InterruptedKey] = "true"
+	wrapper.addRunnerEventLocked()
+	return true
+}
@@ -0,0 +1,110 @@
+package epsagon_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/epsagon/epsagon-go/epsagon"
+	"github.com/epsagon/epsagon-go/epsagontest"
+	"github.com/epsagon/epsagon-go/protocol"
+	"github.com/epsagon/epsagon-go/tracer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Shutdown", func() {
+	var (
+		collector *epsagontest.FakeCollector
+		config    *epsagon.Config
+	)
+
+	BeforeEach(func() {
+		tracer.GlobalTracer = nil
+		collector = epsagontest.NewFakeCollector("test token")
+		config = epsagon.NewTracerConfig("test", "test token")
+		config.CollectorURL = collector.URL
+	})
+
+	AfterEach(func() {
+		collector.Close()
+		tracer.GlobalTracer = nil
+	})
+
+	It("Flushes the trace of a running function with an interrupted runner", func() {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		returned := make(chan struct{})
+		go func() {
+			defer close(returned)
+			epsagon.GoWrapper(config, func() {
+				close(started)
+				<-release
+				epsagon.Label("after", "shutdown")
+			}, "long-running")()
+		}()
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		Expect(epsagon.Shutdown(ctx)).To(Succeed())
+
+		trace, err := collector.WaitForTrace(time.Second)
+		Expect(err).To(BeNil())
+		Expect(trace.Events).To(HaveLen(1))
+		runner := trace.Events[0]
+		Expect(runner.Resource.Name).To(Equal("long-running"))
+		Expect(runner.ErrorCode).To(Equal(protocol.ErrorCode_EXCEPTION))
+		Expect(runner.Exception.Type).To(Equal(epsagon.InterruptedErrorType))
+		Expect(runner.Resource.Metadata[tracer.InterruptedKey]).To(Equal("true"))
+
+		close(release)
+		Eventually(returned).Should(BeClosed())
+		Consistently(collector.Traces).Should(HaveLen(1))
+	})
+
+	It("Adds a single runner when a function panics during the shutdown", func() {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		returned := make(chan struct{})
+		go func() {
+			defer close(returned)
+			defer func() { recover() }()
+			epsagon.GoWrapper(config, func() {
+				close(started)
+				<-release
+				panic("failed during shutdown")
+			}, "panicking")()
+		}()
+		<-started
+
+		shutdownErr := make(chan error, 1)
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			shutdownErr <- epsagon.Shutdown(ctx)
+		}()
+		close(release)
+		Eventually(returned).Should(BeClosed())
+		Eventually(shutdownErr).Should(Receive(BeNil()))
+
+		trace, err := collector.WaitForTrace(time.Second)
+		Expect(err).To(BeNil())
+		Expect(trace.Events).To(HaveLen(1))
+		runner := trace.Events[0]
+		Expect(runner.ErrorCode).To(Equal(protocol.ErrorCode_EXCEPTION))
+		Expect(runner.Exception.Type).To(BeElementOf(epsagon.InterruptedErrorType, "Runtime Error"))
+	})
+
+	It("Returns the context error when the flush does not finish in time", func() {
+		collector.SetResponseDelay(time.Second)
+		config.SendTimeout = "2s"
+		tracer.CreateGlobalTracer(&config.Config).Start()
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		Expect(epsagon.Shutdown(ctx)).To(Equal(context.DeadlineExceeded))
+	})
+
+	It("Does nothing without a tracer", func() {
+		Expect(epsagon.Shutdown(context.Background())).To(Succeed())
+	})
+})
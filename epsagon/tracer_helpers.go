@@ -3,6 +3,7 @@ package epsagon
 import (
 	"context"
 
+	"github.com/aws/aws-lambda-go/lambdacontext"
 	"github.com/epsagon/epsagon-go/tracer"
 )
 
@@ -10,6 +11,14 @@ type tracerKey string
 
 const tracerKeyValue tracerKey = "tracer"
 
+const requestIDKeyValue tracerKey = "request_id"
+
+// RequestIDHeader is the incoming request header used as the request ID by the server wrappers
+const RequestIDHeader = "X-Request-Id"
+
+// TraceIDLogKey is the key of the trace identifier added to log records by the logging adapters
+const TraceIDLogKey = "epsagon.trace_id"
+
 // ContextWithTracer creates a context with given tracer
 func ContextWithTracer(t tracer.Tracer, ctx ...context.Context) context.Context {
 	if len(ctx) == 1 {
@@ -40,3 +49,58 @@ func ExtractTracer(ctx []context.Context) tracer.Tracer {
 	}
 	return tracerValue
 }
+
+// TracerFromContext returns the tracer of the given context, or the global tracer
+// if the context holds no tracer. Unlike ExtractTracer it never panics, and
+// returns nil if no running tracer is found
+func TracerFromContext(ctx context.Context) tracer.Tracer {
+	if ctx != nil {
+		if tracerValue, ok := ctx.Value(tracerKeyValue).(tracer.Tracer); ok {
+			if tracerValue == nil || tracerValue.Stopped() {
+				return nil
+			}
+			return tracerValue
+		}
+	}
+	return ExtractTracer(nil)
+}
+
+// ContextWithRequestID creates a context that holds the given request ID
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKeyValue, requestID)
+}
+
+// TraceID returns the ID of the trace the given context runs in,
+// or an empty string if no running tracer is found
+func TraceID(ctx context.Context) string {
+	currentTracer := TracerFromContext(ctx)
+	if currentTracer == nil {
+		return ""
+	}
+	return tracer.GetTracerTraceID(currentTracer)
+}
+
+// RequestID returns the ID of the request the given context handles: the
+// AWS request ID in Lambda functions, or the request ID set by the server
+// wrappers. Returns an empty string if none is found
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if requestID, ok := ctx.Value(requestIDKeyValue).(string); ok {
+		return requestID
+	}
+	if lambdaContext, ok := lambdacontext.FromContext(ctx); ok {
+		return lambdaContext.AwsRequestID
+	}
+	return ""
+}
+
+// ServerRequestID returns the request ID of an incoming server request: the
+// value of its RequestIDHeader if given, or the trace ID otherwise
+func ServerRequestID(headerValue string, wrapperTracer tracer.Tracer) string {
+	if len(headerValue) > 0 {
+		return headerValue
+	}
+	return tracer.GetTracerTraceID(wrapperTracer)
+}
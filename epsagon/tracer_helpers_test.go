@@ -0,0 +1,36 @@
+package epsagon_test
+
+import (
+	"context"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/epsagon/epsagon-go/epsagon"
+	"github.com/epsagon/epsagon-go/tracer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Trace identifiers", func() {
+	AfterEach(func() {
+		tracer.GlobalTracer = nil
+	})
+
+	It("Returns the trace ID of the context tracer", func() {
+		tracer.GlobalTracer = &tracer.MockedEpsagonTracer{TraceID: "global-trace-id"}
+		contextTracer := &tracer.MockedEpsagonTracer{TraceID: "context-trace-id"}
+		Expect(epsagon.TraceID(epsagon.ContextWithTracer(contextTracer))).To(Equal("context-trace-id"))
+		Expect(epsagon.TraceID(context.Background())).To(Equal("global-trace-id"))
+	})
+
+	It("Returns empty identifiers without a tracer or request", func() {
+		Expect(epsagon.TraceID(context.Background())).To(BeEmpty())
+		Expect(epsagon.RequestID(context.Background())).To(BeEmpty())
+	})
+
+	It("Returns the AWS request ID in Lambda functions", func() {
+		ctx := lambdacontext.NewContext(
+			context.Background(), &lambdacontext.LambdaContext{AwsRequestID: "aws-request-id"})
+		Expect(epsagon.RequestID(ctx)).To(Equal("aws-request-id"))
+		Expect(epsagon.RequestID(epsagon.ContextWithRequestID(ctx, "request-id"))).To(Equal("request-id"))
+	})
+})
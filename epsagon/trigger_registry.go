@@ -0,0 +1,100 @@
+package epsagon
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/epsagon/epsagon-go/tracer"
+)
+
+// TriggerDetector returns whether a Lambda event payload is of a custom trigger
+type TriggerDetector func(payload json.RawMessage) bool
+
+type customTrigger struct {
+	name     string
+	detector TriggerDetector
+	factoryAndType
+}
+
+var (
+	customTriggersLock sync.RWMutex
+	customTriggers     []customTrigger
+)
+
+// RegisterTriggerFactory registers a custom Lambda trigger. Payloads the
+// detector matches are decoded to a new value of eventType and passed to the
+// factory, or passed as a json.RawMessage when eventType is nil. Registered
+// triggers take priority over the built-in ones, in registration order.
+// Registering an existing name replaces it, a trigger without a name, detector
+// or factory is ignored
+func RegisterTriggerFactory(name string, detector TriggerDetector, eventType reflect.Type, factory TriggerFactory) {
+	if len(name) == 0 || detector == nil || factory == nil {
+		tracer.GetGlobalTracerConfig().GetLogger().Warnf(
+			"ignoring the custom trigger %q, a name, a detector and a factory are required", name)
+		return
+	}
+	registered := customTrigger{
+		name:     name,
+		detector: detector,
+		factoryAndType: factoryAndType{
+			EventType: eventType,
+			Factory:   factory,
+		},
+	}
+	customTriggersLock.Lock()
+	defer customTriggersLock.Unlock()
+	for i, trigger := range customTriggers {
+		if trigger.name == name {
+			replaced := append([]customTrigger{}, customTriggers...)
+			replaced[i] = registered
+			customTriggers = replaced
+			return
+		}
+	}
+	customTriggers = append(customTriggers, registered)
+}
+
+// UnregisterTriggerFactory removes a custom Lambda trigger
+func UnregisterTriggerFactory(name string) {
+	customTriggersLock.Lock()
+	defer customTriggersLock.Unlock()
+	for i, trigger := range customTriggers {
+		if trigger.name == name {
+			customTriggers = append(customTriggers[:i:i], customTriggers[i+1:]...)
+			return
+		}
+	}
+}
+
+// detectCustomTrigger returns the factory of the first registered custom
+// trigger that matches the payload. A panicking detector does not match
+func detectCustomTrigger(payload json.RawMessage, currentTracer tracer.Tracer) (factoryAndType, bool) {
+	customTriggersLock.RLock()
+	triggers := customTriggers
+	customTriggersLock.RUnlock()
+	for _, trigger := range triggers {
+		if trigger.detect(payload, currentTracer) {
+			return trigger.factoryAndType, true
+		}
+	}
+	return factoryAndType{}, false
+}
+
+func (trigger customTrigger) detect(payload json.RawMessage, currentTracer tracer.Tracer) (matched bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			matched = false
+			message := fmt.Sprintf("custom trigger %s detector panicked: %+v", trigger.name, r)
+			if currentTracer == nil {
+				tracer.GetGlobalTracerConfig().GetLogger().Warnf("%s", message)
+				return
+			}
+			currentTracer.GetConfig().GetLogger().Warnf("%s", message)
+			currentTracer.AddException(tracer.NewTracerException(
+				currentTracer, "trigger-identification", message, nil))
+		}
+	}()
+	return trigger.detector(payload)
+}
@@ -0,0 +1,174 @@
+package epsagon
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"reflect"
+
+	lambdaEvents "github.com/aws/aws-lambda-go/events"
+	"github.com/epsagon/epsagon-go/protocol"
+	"github.com/epsagon/epsagon-go/tracer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type orderEnvelope struct {
+	OrderID string `json:"orderEnvelopeId"`
+	Shop    string `json:"shop"`
+	Payload string `json:"payload"`
+}
+
+func detectOrderEnvelope(payload json.RawMessage) bool {
+	return bytes.Contains(payload, []byte(`"orderEnvelopeId"`))
+}
+
+func triggerOrderEnvelope(rawEvent interface{}, metadataOnly bool) *protocol.Event {
+	event := rawEvent.(orderEnvelope)
+	triggerEvent := &protocol.Event{
+		Id:     event.OrderID,
+		Origin: "trigger",
+		Resource: &protocol.Resource{
+			Name:      event.Shop,
+			Type:      "orders",
+			Operation: "order",
+			Metadata:  map[string]string{},
+		},
+	}
+	if !metadataOnly {
+		triggerEvent.Resource.Metadata["payload"] = event.Payload
+	}
+	return triggerEvent
+}
+
+var _ = Describe("RegisterTriggerFactory", func() {
+	var (
+		events     []*protocol.Event
+		exceptions []*protocol.Exception
+	)
+	BeforeEach(func() {
+		events = make([]*protocol.Event, 0)
+		exceptions = make([]*protocol.Exception, 0)
+		tracer.GlobalTracer = &tracer.MockedEpsagonTracer{
+			Events:     &events,
+			Exceptions: &exceptions,
+		}
+	})
+	AfterEach(func() {
+		UnregisterTriggerFactory("orders")
+		UnregisterTriggerFactory("sqs-orders")
+		UnregisterTriggerFactory("broken")
+	})
+
+	It("Creates the trigger of a custom event", func() {
+		RegisterTriggerFactory("orders", detectOrderEnvelope, reflect.TypeOf(orderEnvelope{}), triggerOrderEnvelope)
+		payload := json.RawMessage(`{"orderEnvelopeId": "order-1", "shop": "main", "payload": "data"}`)
+		addLambdaTrigger(payload, false, triggerFactories, tracer.GlobalTracer)
+		Expect(events).To(HaveLen(1))
+		Expect(events[0].Id).To(Equal("order-1"))
+		Expect(events[0].Resource.Type).To(Equal("orders"))
+		Expect(events[0].Resource.Name).To(Equal("main"))
+		Expect(events[0].Resource.Metadata["payload"]).To(Equal("data"))
+	})
+
+	It("Passes the metadata only flag", func() {
+		RegisterTriggerFactory("orders", detectOrderEnvelope, reflect.TypeOf(orderEnvelope{}), triggerOrderEnvelope)
+		payload := json.RawMessage(`{"orderEnvelopeId": "order-1", "shop": "main", "payload": "data"}`)
+		addLambdaTrigger(payload, true, triggerFactories, tracer.GlobalTracer)
+		Expect(events).To(HaveLen(1))
+		Expect(events[0].Resource.Metadata).NotTo(HaveKey("payload"))
+	})
+
+	It("Takes priority over the built-in triggers", func() {
+		RegisterTriggerFactory("sqs-orders", func(payload json.RawMessage) bool {
+			return bytes.Contains(payload, []byte("orders-queue"))
+		}, nil, func(rawEvent interface{}, metadataOnly bool) *protocol.Event {
+			Expect(rawEvent).To(BeAssignableToTypeOf(json.RawMessage{}))
+			return &protocol.Event{
+				Origin:   "trigger",
+				Resource: &protocol.Resource{Type: "orders", Metadata: map[string]string{}},
+			}
+		})
+		payload, err := json.Marshal(lambdaEvents.SQSEvent{
+			Records: []lambdaEvents.SQSMessage{{
+				MessageId:      "message-1",
+				EventSource:    "aws:sqs",
+				EventSourceARN: "arn:aws:sqs:us-east-1:123456789012:orders-queue",
+			}},
+		})
+		Expect(err).To(BeNil())
+		addLambdaTrigger(payload, false, triggerFactories, tracer.GlobalTracer)
+		Expect(events).To(HaveLen(1))
+		Expect(events[0].Resource.Type).To(Equal("orders"))
+
+		UnregisterTriggerFactory("sqs-orders")
+		addLambdaTrigger(payload, false, triggerFactories, tracer.GlobalTracer)
+		Expect(events).To(HaveLen(2))
+		Expect(events[1].Resource.Type).To(Equal("sqs"))
+	})
+
+	It("Replaces a trigger registered with the same name", func() {
+		RegisterTriggerFactory("orders", detectOrderEnvelope, reflect.TypeOf(orderEnvelope{}), triggerOrderEnvelope)
+		RegisterTriggerFactory("orders", detectOrderEnvelope, nil, func(rawEvent interface{}, metadataOnly bool) *protocol.Event {
+			return nil
+		})
+		addLambdaTrigger(json.RawMessage(`{"orderEnvelopeId": "order-1"}`), false, triggerFactories, tracer.GlobalTracer)
+		Expect(events).To(BeEmpty())
+	})
+
+	It("Does not change the triggers a detection is iterating when replacing one", func() {
+		RegisterTriggerFactory("orders", detectOrderEnvelope, reflect.TypeOf(orderEnvelope{}), triggerOrderEnvelope)
+		customTriggersLock.RLock()
+		snapshot := customTriggers
+		customTriggersLock.RUnlock()
+		RegisterTriggerFactory("orders", detectOrderEnvelope, nil, func(rawEvent interface{}, metadataOnly bool) *protocol.Event {
+			return nil
+		})
+		Expect(snapshot).To(HaveLen(1))
+		Expect(snapshot[0].EventType).To(Equal(reflect.TypeOf(orderEnvelope{})))
+		Expect(customTriggers[0].EventType).To(BeNil())
+	})
+
+	It("Falls back to the built-in triggers when a detector panics", func() {
+		var buf bytes.Buffer
+		tracer.SetDefaultLogger(tracer.NewStdLogger(log.New(&buf, "", 0)))
+		defer tracer.SetDefaultLogger(nil)
+		RegisterTriggerFactory("broken", func(payload json.RawMessage) bool {
+			panic("broken detector")
+		}, nil, triggerOrderEnvelope)
+		RegisterTriggerFactory("orders", detectOrderEnvelope, reflect.TypeOf(orderEnvelope{}), triggerOrderEnvelope)
+
+		addLambdaTrigger(json.RawMessage(`{"orderEnvelopeId": "order-1", "shop": "main"}`), false, triggerFactories, tracer.GlobalTracer)
+		Expect(events).To(HaveLen(1))
+		Expect(events[0].Resource.Type).To(Equal("orders"))
+		addLambdaTrigger(json.RawMessage(`{"hello": "world"}`), false, triggerFactories, tracer.GlobalTracer)
+		Expect(events).To(HaveLen(2))
+		Expect(events[1].Resource.Type).To(Equal("json"))
+		Expect(exceptions).To(HaveLen(2))
+		Expect(exceptions[0].Type).To(Equal("trigger-identification"))
+		Expect(exceptions[0].Message).To(ContainSubstring("broken detector"))
+		Expect(buf.String()).To(ContainSubstring("custom trigger broken detector panicked"))
+	})
+
+	It("Logs and ignores incomplete registrations", func() {
+		var buf bytes.Buffer
+		tracer.SetDefaultLogger(tracer.NewStdLogger(log.New(&buf, "", 0)))
+		defer tracer.SetDefaultLogger(nil)
+		RegisterTriggerFactory("", detectOrderEnvelope, reflect.TypeOf(orderEnvelope{}), triggerOrderEnvelope)
+		RegisterTriggerFactory("orders", nil, reflect.TypeOf(orderEnvelope{}), triggerOrderEnvelope)
+		RegisterTriggerFactory("orders", detectOrderEnvelope, reflect.TypeOf(orderEnvelope{}), nil)
+		Expect(customTriggers).To(BeEmpty())
+		Expect(bytes.Count(buf.Bytes(), []byte("EPSAGON WARN: ignoring the custom trigger"))).To(Equal(3))
+
+		addLambdaTrigger(json.RawMessage(`{"orderEnvelopeId": "order-1"}`), false, triggerFactories, tracer.GlobalTracer)
+		Expect(events).To(HaveLen(1))
+		Expect(events[0].Resource.Type).To(Equal("json"))
+	})
+
+	It("Falls back to the built-in triggers when no detector matches", func() {
+		RegisterTriggerFactory("orders", detectOrderEnvelope, reflect.TypeOf(orderEnvelope{}), triggerOrderEnvelope)
+		addLambdaTrigger(json.RawMessage(`{"hello": "world"}`), false, triggerFactories, tracer.GlobalTracer)
+		Expect(events).To(HaveLen(1))
+		Expect(events[0].Resource.Type).To(Equal("json"))
+	})
+})
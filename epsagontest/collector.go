@@ -0,0 +1,230 @@
+package epsagontest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/epsagon/epsagon-go/protocol"
+	"github.com/epsagon/epsagon-go/tracer"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+)
+
+// ProtobufContentType is the content type of protobuf encoded traces
+const ProtobufContentType = "application/x-protobuf"
+
+// FakeCollector is an HTTP trace collector for end-to-end tests. It decodes
+// posted traces (JSON or protobuf, optionally gzipped), checks the
+// Authorization header and records every received trace. It can also respond
+// with errors or respond slowly to exercise the tracer send paths
+type FakeCollector struct {
+	// URL is the collector url to set as the tracer CollectorURL
+	URL string
+
+	server   *httptest.Server
+	token    string
+	lock     sync.Mutex
+	traces   []*protocol.Trace
+	requests int
+	rejected int
+	status   int
+	delay    time.Duration
+	received chan struct{}
+}
+
+// NewFakeCollector starts a fake collector that accepts traces sent with the
+// given token, an empty token accepts traces sent with any token
+func NewFakeCollector(token string) *FakeCollector {
+	collector := &FakeCollector{
+		token:    token,
+		status:   http.StatusOK,
+		received: make(chan struct{}),
+	}
+	collector.server = httptest.NewServer(http.HandlerFunc(collector.handle))
+	collector.URL = collector.server.URL
+	return collector
+}
+
+// Close shuts down the collector, blocking until all requests are done
+func (collector *FakeCollector) Close() {
+	collector.server.CloseClientConnections()
+	collector.server.Close()
+}
+
+// TracerConfig returns a tracer config that sends traces to the collector
+func (collector *FakeCollector) TracerConfig(applicationName string) *tracer.Config {
+	token := collector.token
+	if len(token) == 0 {
+		token = "fake-collector-token"
+	}
+	return &tracer.Config{
+		ApplicationName: applicationName,
+		Token:           token,
+		CollectorURL:    collector.URL,
+		MetadataOnly:    true,
+		SendTimeout:     "1s",
+	}
+}
+
+// RespondWith sets the status code of the collector responses, traces are
+// only recorded when it is a 2xx status code
+func (collector *FakeCollector) RespondWith(statusCode int) {
+	collector.lock.Lock()
+	defer collector.lock.Unlock()
+	collector.status = statusCode
+}
+
+// SetResponseDelay delays the collector responses, a delay longer than the
+// tracer SendTimeout simulates a collector timeout
+func (collector *FakeCollector) SetResponseDelay(delay time.Duration) {
+	collector.lock.Lock()
+	defer collector.lock.Unlock()
+	collector.delay = delay
+}
+
+// Traces returns a copy of the received traces
+func (collector *FakeCollector) Traces() []*protocol.Trace {
+	collector.lock.Lock()
+	defer collector.lock.Unlock()
+	return append([]*protocol.Trace{}, collector.traces...)
+}
+
+// Requests returns the number of requests the collector received
+func (collector *FakeCollector) Requests() int {
+	collector.lock.Lock()
+	defer collector.lock.Unlock()
+	return collector.requests
+}
+
+// RejectedRequests returns the number of requests that were rejected because
+// of an invalid Authorization header or an undecodable body
+func (collector *FakeCollector) RejectedRequests() int {
+	collector.lock.Lock()
+	defer collector.lock.Unlock()
+	return collector.rejected
+}
+
+// WaitForTraces waits until the collector recorded at least count traces
+// and returns them, or returns an error after the timeout
+func (collector *FakeCollector) WaitForTraces(count int, timeout time.Duration) ([]*protocol.Trace, error) {
+	deadline := time.After(timeout)
+	for {
+		collector.lock.Lock()
+		if len(collector.traces) >= count {
+			traces := append([]*protocol.Trace{}, collector.traces...)
+			collector.lock.Unlock()
+			return traces, nil
+		}
+		received := collector.received
+		collector.lock.Unlock()
+		select {
+		case <-received:
+		case <-deadline:
+			return nil, fmt.Errorf("timeout while waiting for %d traces, received %d", count, len(collector.Traces()))
+		}
+	}
+}
+
+// WaitForTrace waits for the first trace recorded by the collector
+func (collector *FakeCollector) WaitForTrace(timeout time.Duration) (*protocol.Trace, error) {
+	traces, err := collector.WaitForTraces(1, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return traces[0], nil
+}
+
+func (collector *FakeCollector) handle(res http.ResponseWriter, req *http.Request) {
+	collector.lock.Lock()
+	collector.requests++
+	status, delay := collector.status, collector.delay
+	collector.lock.Unlock()
+
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-req.Context().Done():
+			return
+		}
+	}
+	if req.Method != http.MethodPost {
+		collector.reject(res, http.StatusMethodNotAllowed, "only POST is supported")
+		return
+	}
+	if !collector.authorized(req) {
+		collector.reject(res, http.StatusUnauthorized, "invalid token")
+		return
+	}
+	trace, err := decodeTrace(req)
+	if err != nil {
+		collector.reject(res, http.StatusBadRequest, err.Error())
+		return
+	}
+	if status < http.StatusOK || status >= http.StatusMultipleChoices {
+		res.WriteHeader(status)
+		res.Write([]byte("fake collector error"))
+		return
+	}
+
+	collector.lock.Lock()
+	collector.traces = append(collector.traces, trace)
+	close(collector.received)
+	collector.received = make(chan struct{})
+	collector.lock.Unlock()
+	res.WriteHeader(status)
+}
+
+func (collector *FakeCollector) authorized(req *http.Request) bool {
+	authorization := req.Header.Get("Authorization")
+	if !strings.HasPrefix(authorization, "Bearer ") {
+		return false
+	}
+	token := strings.TrimPrefix(authorization, "Bearer ")
+	return len(token) > 0 && (len(collector.token) == 0 || token == collector.token)
+}
+
+func (collector *FakeCollector) reject(res http.ResponseWriter, status int, message string) {
+	collector.lock.Lock()
+	collector.rejected++
+	collector.lock.Unlock()
+	http.Error(res, message, status)
+}
+
+func decodeTrace(req *http.Request) (*protocol.Trace, error) {
+	var body io.Reader = req.Body
+	if req.Header.Get("Content-Encoding") == "gzip" {
+		gzipReader, err := gzip.NewReader(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer gzipReader.Close()
+		body = gzipReader
+	}
+	buf, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	if len(buf) == 0 {
+		return nil, errors.New("empty trace")
+	}
+	var trace protocol.Trace
+	if strings.HasPrefix(req.Header.Get("Content-Type"), ProtobufContentType) {
+		err = proto.Unmarshal(buf, &trace)
+	} else {
+		unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+		err = unmarshaler.Unmarshal(bytes.NewReader(buf), &trace)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &trace, nil
+}
@@ -0,0 +1,109 @@
+package epsagontest_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"time"
+
+	"github.com/epsagon/epsagon-go/epsagontest"
+	"github.com/epsagon/epsagon-go/protocol"
+	"github.com/epsagon/epsagon-go/tracer"
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func postTrace(url, token, contentType string, body []byte, gzipped bool) *http.Response {
+	if gzipped {
+		var buf bytes.Buffer
+		writer := gzip.NewWriter(&buf)
+		writer.Write(body)
+		writer.Close()
+		body = buf.Bytes()
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	Expect(err).To(BeNil())
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Authorization", "Bearer "+token)
+	if gzipped {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	Expect(err).To(BeNil())
+	resp.Body.Close()
+	return resp
+}
+
+var _ = Describe("FakeCollector", func() {
+	var collector *epsagontest.FakeCollector
+
+	BeforeEach(func() {
+		collector = epsagontest.NewFakeCollector("test-token")
+	})
+
+	AfterEach(func() {
+		collector.Close()
+	})
+
+	It("Receives traces sent by the tracer", func() {
+		config := collector.TracerConfig("test-app")
+		testTracer := tracer.CreateTracer(config)
+		testTracer.Start()
+		testTracer.AddEvent(&protocol.Event{
+			Id:     "event-id",
+			Origin: "runner",
+			Resource: &protocol.Resource{
+				Name:      "resource",
+				Type:      "go-function",
+				Operation: "invoke",
+				Metadata:  map[string]string{},
+			},
+		})
+		testTracer.Stop()
+		trace, err := collector.WaitForTrace(time.Second)
+		Expect(err).To(BeNil())
+		Expect(trace.AppName).To(Equal("test-app"))
+		Expect(trace.Token).To(Equal("test-token"))
+		Expect(trace.Events).To(HaveLen(1))
+		Expect(trace.Events[0].Id).To(Equal("event-id"))
+	})
+
+	It("Decodes gzipped protobuf traces", func() {
+		body, err := proto.Marshal(&protocol.Trace{AppName: "protobuf-app"})
+		Expect(err).To(BeNil())
+		resp := postTrace(collector.URL, "test-token", epsagontest.ProtobufContentType, body, true)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(collector.Traces()).To(HaveLen(1))
+		Expect(collector.Traces()[0].AppName).To(Equal("protobuf-app"))
+	})
+
+	It("Rejects traces with an invalid token", func() {
+		resp := postTrace(collector.URL, "other-token", "application/json", []byte(`{"app_name": "app"}`), false)
+		Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+		Expect(collector.RejectedRequests()).To(Equal(1))
+		Expect(collector.Traces()).To(BeEmpty())
+	})
+
+	It("Simulates collector errors", func() {
+		collector.RespondWith(http.StatusServiceUnavailable)
+		resp := postTrace(collector.URL, "test-token", "application/json", []byte(`{"app_name": "app"}`), false)
+		Expect(resp.StatusCode).To(Equal(http.StatusServiceUnavailable))
+		Expect(collector.Requests()).To(Equal(1))
+		Expect(collector.Traces()).To(BeEmpty())
+	})
+
+	It("Simulates collector timeouts", func() {
+		collector.SetResponseDelay(2 * time.Second)
+		config := collector.TracerConfig("test-app")
+		config.SendTimeout = "100ms"
+		testTracer := tracer.CreateTracer(config)
+		testTracer.Start()
+		start := time.Now()
+		testTracer.Stop()
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+		Expect(collector.Traces()).To(BeEmpty())
+		_, err := collector.WaitForTrace(100 * time.Millisecond)
+		Expect(err).NotTo(BeNil())
+	})
+})
@@ -0,0 +1,53 @@
+package epsagontest
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/epsagon/epsagon-go/tracer"
+)
+
+// FixedClock returns a tracer.Clock that always returns the given time
+func FixedClock(t time.Time) tracer.Clock {
+	return func() time.Time { return t }
+}
+
+// StepClock returns a tracer.Clock that starts at the given time and advances
+// by step on every call, so every traced event gets a known, distinct timestamp
+func StepClock(start time.Time, step time.Duration) tracer.Clock {
+	var lock sync.Mutex
+	next := start
+	return func() time.Time {
+		lock.Lock()
+		defer lock.Unlock()
+		now := next
+		next = next.Add(step)
+		return now
+	}
+}
+
+// SequentialIDs returns a tracer.IDGenerator generating the identifiers
+// prefix-1, prefix-2, ... in order
+func SequentialIDs(prefix string) tracer.IDGenerator {
+	var lock sync.Mutex
+	counter := 0
+	return func() string {
+		lock.Lock()
+		defer lock.Unlock()
+		counter++
+		return fmt.Sprintf("%s-%d", prefix, counter)
+	}
+}
+
+// Deterministic sets a StepClock starting at the given time and advancing by a
+// millisecond, and SequentialIDs with the "id" prefix on the given config, and
+// returns it
+func Deterministic(config *tracer.Config, start time.Time) *tracer.Config {
+	if config == nil {
+		config = &tracer.Config{}
+	}
+	config.Clock = StepClock(start, time.Millisecond)
+	config.IDGenerator = SequentialIDs("id")
+	return config
+}
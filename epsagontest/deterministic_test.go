@@ -0,0 +1,67 @@
+package epsagontest_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/epsagon/epsagon-go/epsagon"
+	"github.com/epsagon/epsagon-go/epsagontest"
+	"github.com/epsagon/epsagon-go/tracer"
+	"github.com/golang/protobuf/jsonpb"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Deterministic traces", func() {
+	start := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	It("StepClock advances by step on every call", func() {
+		clock := epsagontest.StepClock(start, time.Second)
+		Expect(clock()).To(Equal(start))
+		Expect(clock()).To(Equal(start.Add(time.Second)))
+		Expect(clock()).To(Equal(start.Add(2 * time.Second)))
+	})
+
+	It("FixedClock always returns the same time", func() {
+		clock := epsagontest.FixedClock(start)
+		Expect(clock()).To(Equal(start))
+		Expect(clock()).To(Equal(start))
+	})
+
+	It("SequentialIDs generates prefixed sequential ids", func() {
+		generator := epsagontest.SequentialIDs("event")
+		Expect(generator()).To(Equal("event-1"))
+		Expect(generator()).To(Equal("event-2"))
+	})
+
+	It("Uses the config clock and ID generator in config methods", func() {
+		config := epsagontest.Deterministic(nil, start)
+		Expect(config.GetTimestamp()).To(Equal(tracer.Timestamp(start)))
+		Expect(config.NewID()).To(Equal("id-1"))
+	})
+
+	It("Produces identical traces for identical runs", func() {
+		collector := epsagontest.NewFakeCollector("test-token")
+		defer collector.Close()
+		runOnce := func() string {
+			config := epsagontest.Deterministic(collector.TracerConfig("test-app"), start)
+			wrapper := epsagon.ConcurrentGoWrapper(
+				&epsagon.Config{Config: *config},
+				func(ctx context.Context) {},
+				"deterministic",
+			)
+			wrapper()
+			trace, err := collector.WaitForTrace(time.Second)
+			Expect(err).To(BeNil())
+			for _, event := range trace.Events {
+				Expect(event.Id).To(HavePrefix("id-"))
+				Expect(event.StartTime).To(BeNumerically(">=", tracer.Timestamp(start)))
+			}
+			traceJSON, err := (&jsonpb.Marshaler{}).MarshalToString(trace)
+			Expect(err).To(BeNil())
+			return traceJSON
+		}
+		first := runOnce()
+		Expect(runOnce()).To(Equal(first))
+	})
+})
@@ -0,0 +1,122 @@
+package epsagontest
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/epsagon/epsagon-go/protocol"
+	"github.com/epsagon/epsagon-go/tracer"
+	"github.com/onsi/gomega/types"
+)
+
+type haveLabelMatcher struct {
+	key   string
+	value interface{}
+}
+
+// HaveLabel matches a Recorder that recorded the label key with the given value
+func HaveLabel(key string, value interface{}) types.GomegaMatcher {
+	return &haveLabelMatcher{key: key, value: value}
+}
+
+func (matcher *haveLabelMatcher) Match(actual interface{}) (bool, error) {
+	recorder, ok := actual.(*Recorder)
+	if !ok {
+		return false, fmt.Errorf("HaveLabel expects a *Recorder, got %T", actual)
+	}
+	value, ok := recorder.Labels()[matcher.key]
+	return ok && reflect.DeepEqual(value, matcher.value), nil
+}
+
+func (matcher *haveLabelMatcher) FailureMessage(actual interface{}) string {
+	return fmt.Sprintf("Expected\n\t%v\nto have label %q with value %#v", actual, matcher.key, matcher.value)
+}
+
+func (matcher *haveLabelMatcher) NegatedFailureMessage(actual interface{}) string {
+	return fmt.Sprintf("Expected\n\t%v\nnot to have label %q with value %#v", actual, matcher.key, matcher.value)
+}
+
+type haveErrorCodeMatcher struct {
+	errorCode protocol.ErrorCode
+}
+
+// HaveErrorCode matches an event with the given error code
+func HaveErrorCode(errorCode protocol.ErrorCode) types.GomegaMatcher {
+	return &haveErrorCodeMatcher{errorCode: errorCode}
+}
+
+func (matcher *haveErrorCodeMatcher) Match(actual interface{}) (bool, error) {
+	event, ok := actual.(*protocol.Event)
+	if !ok || event == nil {
+		return false, fmt.Errorf("HaveErrorCode expects a non nil *protocol.Event, got %T", actual)
+	}
+	return event.ErrorCode == matcher.errorCode, nil
+}
+
+func (matcher *haveErrorCodeMatcher) FailureMessage(actual interface{}) string {
+	return fmt.Sprintf("Expected event\n\t%v\nto have error code %v", actual, matcher.errorCode)
+}
+
+func (matcher *haveErrorCodeMatcher) NegatedFailureMessage(actual interface{}) string {
+	return fmt.Sprintf("Expected event\n\t%v\nnot to have error code %v", actual, matcher.errorCode)
+}
+
+type haveMaskedKeyMatcher struct {
+	key string
+}
+
+// HaveMaskedKey matches an event whose resource metadata has the key masked,
+// either as a metadata key or as a nested key of a json metadata value
+func HaveMaskedKey(key string) types.GomegaMatcher {
+	return &haveMaskedKeyMatcher{key: key}
+}
+
+func (matcher *haveMaskedKeyMatcher) Match(actual interface{}) (bool, error) {
+	event, ok := actual.(*protocol.Event)
+	if !ok || event == nil || event.Resource == nil {
+		return false, fmt.Errorf("HaveMaskedKey expects a non nil *protocol.Event with a resource, got %T", actual)
+	}
+	for key, value := range event.Resource.Metadata {
+		if key == matcher.key {
+			if value == tracer.MaskedValue {
+				return true, nil
+			}
+			continue
+		}
+		var decodedJSON interface{}
+		if json.Unmarshal([]byte(value), &decodedJSON) == nil && hasMaskedNestedKey(decodedJSON, matcher.key) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (matcher *haveMaskedKeyMatcher) FailureMessage(actual interface{}) string {
+	return fmt.Sprintf("Expected event\n\t%v\nto have key %q masked", actual, matcher.key)
+}
+
+func (matcher *haveMaskedKeyMatcher) NegatedFailureMessage(actual interface{}) string {
+	return fmt.Sprintf("Expected event\n\t%v\nnot to have key %q masked", actual, matcher.key)
+}
+
+func hasMaskedNestedKey(decodedJSON interface{}, key string) bool {
+	switch value := decodedJSON.(type) {
+	case []interface{}:
+		for _, nested := range value {
+			if hasMaskedNestedKey(nested, key) {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		for nestedKey, nested := range value {
+			if nestedKey == key && nested == tracer.MaskedValue {
+				return true
+			}
+			if hasMaskedNestedKey(nested, key) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,291 @@
+// Package epsagontest provides an in-memory tracer and assertions for testing
+// code instrumented with Epsagon
+package epsagontest
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/epsagon/epsagon-go/protocol"
+	"github.com/epsagon/epsagon-go/tracer"
+)
+
+// MetricObservation is a custom metric observation recorded by the Recorder
+type MetricObservation struct {
+	Kind  tracer.MetricKind
+	Name  string
+	Value float64
+	Unit  string
+}
+
+// Recorder is a concurrency-safe tracer.Tracer that records everything reported
+// to it in memory instead of sending traces. When stopped, the ignored keys of
+// its config are masked in the recorded events, like they are in sent traces
+type Recorder struct {
+	lock            sync.Mutex
+	config          *tracer.Config
+	traceID         string
+	events          []*protocol.Event
+	exceptions      []*protocol.Exception
+	labels          map[string]interface{}
+	metrics         []MetricObservation
+	logs            []tracer.LogRecord
+	runnerException *protocol.Exception
+	running         bool
+	stopped         bool
+}
+
+// NewRecorder creates a Recorder with the given config, or an empty config if nil
+func NewRecorder(config *tracer.Config) *Recorder {
+	if config == nil {
+		config = &tracer.Config{}
+	}
+	return &Recorder{
+		config:  config,
+		traceID: config.NewID(),
+		labels:  make(map[string]interface{}),
+	}
+}
+
+// NewGlobalRecorder creates a Recorder and sets it as the global tracer.
+// The returned function restores the previous global tracer
+func NewGlobalRecorder(config *tracer.Config) (*Recorder, func()) {
+	recorder := NewRecorder(config)
+	previous := tracer.GlobalTracer
+	tracer.GlobalTracer = recorder
+	return recorder, func() { tracer.GlobalTracer = previous }
+}
+
+// AddEvent implements tracer.Tracer
+func (r *Recorder) AddEvent(event *protocol.Event) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.events = append(r.events, event)
+}
+
+// AddException implements tracer.Tracer
+func (r *Recorder) AddException(exception *protocol.Exception) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.exceptions = append(r.exceptions, exception)
+}
+
+// AddExceptionTypeAndMessage implements tracer.Tracer
+func (r *Recorder) AddExceptionTypeAndMessage(exceptionType, msg string) {
+	r.AddException(tracer.NewTracerException(r, exceptionType, msg, nil))
+}
+
+// AddLabel implements tracer.Tracer
+func (r *Recorder) AddLabel(key string, value interface{}) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.labels[key] = value
+}
+
+// AddMetric implements tracer.Tracer, keeping every observation
+func (r *Recorder) AddMetric(kind tracer.MetricKind, name string, value float64, unit string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.metrics = append(r.metrics, MetricObservation{kind, name, value, unit})
+}
+
+// AddLog implements tracer.Tracer
+func (r *Recorder) AddLog(record tracer.LogRecord) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.logs = append(r.logs, record)
+}
+
+// AddError implements tracer.Tracer, values that are not a string or an
+// error are ignored like they are by the tracer
+func (r *Recorder) AddError(errorType string, value interface{}) {
+	var message string
+	switch typedValue := value.(type) {
+	case string:
+		message = typedValue
+	case error:
+		message = typedValue.Error()
+	default:
+		return
+	}
+	exception := tracer.NewTracerException(r, errorType, message, value)
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.runnerException = exception
+}
+
+// GetRunnerEvent implements tracer.Tracer
+func (r *Recorder) GetRunnerEvent() *protocol.Event {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for _, event := range r.events {
+		if event.Origin == "runner" {
+			return event
+		}
+	}
+	return nil
+}
+
+// Start implements tracer.Tracer
+func (r *Recorder) Start() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.running = true
+	r.stopped = false
+}
+
+// Running implements tracer.Tracer
+func (r *Recorder) Running() bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.running
+}
+
+// SendStopSignal implements tracer.Tracer
+func (r *Recorder) SendStopSignal() {
+	r.Stop()
+}
+
+// Stop implements tracer.Tracer, masking the ignored keys of the recorded events
+func (r *Recorder) Stop() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.stopped {
+		return
+	}
+	r.running = false
+	r.stopped = true
+	for _, event := range r.events {
+		if event.Resource != nil {
+			if err := tracer.MaskIgnoredKeys(event, r.config.IgnoredKeys); err != nil {
+				r.exceptions = append(r.exceptions, tracer.NewTracerException(r,
+					"internal json encode error", err.Error(), err))
+			}
+		}
+	}
+}
+
+// Stopped implements tracer.Tracer
+func (r *Recorder) Stopped() bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.stopped
+}
+
+// GetConfig implements tracer.Tracer
+func (r *Recorder) GetConfig() *tracer.Config {
+	return r.config
+}
+
+// GetTraceID implements tracer.Tracer
+func (r *Recorder) GetTraceID() string {
+	return r.traceID
+}
+
+// Events returns a copy of the recorded events
+func (r *Recorder) Events() []*protocol.Event {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]*protocol.Event{}, r.events...)
+}
+
+// Exceptions returns a copy of the recorded exceptions
+func (r *Recorder) Exceptions() []*protocol.Exception {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]*protocol.Exception{}, r.exceptions...)
+}
+
+// Trace returns the recorded events and exceptions as a trace, for
+// AssertTraceSnapshot
+func (r *Recorder) Trace() *protocol.Trace {
+	return &protocol.Trace{
+		AppName:    r.config.ApplicationName,
+		Events:     r.Events(),
+		Exceptions: r.Exceptions(),
+	}
+}
+
+// Labels returns a copy of the recorded labels
+func (r *Recorder) Labels() map[string]interface{} {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	labels := make(map[string]interface{}, len(r.labels))
+	for key, value := range r.labels {
+		labels[key] = value
+	}
+	return labels
+}
+
+// Metrics returns a copy of the recorded metric observations
+func (r *Recorder) Metrics() []MetricObservation {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]MetricObservation{}, r.metrics...)
+}
+
+// Logs returns a copy of the recorded log lines
+func (r *Recorder) Logs() []tracer.LogRecord {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]tracer.LogRecord{}, r.logs...)
+}
+
+// RunnerException returns the last error set with AddError, or nil
+func (r *Recorder) RunnerException() *protocol.Exception {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.runnerException
+}
+
+// FindEvents returns the recorded events of the given resource type and
+// operation, an empty operation matches all operations
+func (r *Recorder) FindEvents(resourceType, operation string) []*protocol.Event {
+	return FindEvents(r.Events(), resourceType, operation)
+}
+
+// FindEvent returns the first recorded event of the given resource type and
+// operation, or nil if there is none
+func (r *Recorder) FindEvent(resourceType, operation string) *protocol.Event {
+	if events := r.FindEvents(resourceType, operation); len(events) > 0 {
+		return events[0]
+	}
+	return nil
+}
+
+// Reset clears everything recorded so the Recorder can be reused
+func (r *Recorder) Reset() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.events = nil
+	r.exceptions = nil
+	r.labels = make(map[string]interface{})
+	r.metrics = nil
+	r.logs = nil
+	r.runnerException = nil
+	r.running = false
+	r.stopped = false
+}
+
+// String implements fmt.Stringer for readable assertion failures
+func (r *Recorder) String() string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return fmt.Sprintf("Recorder{events: %d, exceptions: %d, labels: %v}",
+		len(r.events), len(r.exceptions), r.labels)
+}
+
+// FindEvents returns the events of the given resource type and operation,
+// an empty operation matches all operations
+func FindEvents(events []*protocol.Event, resourceType, operation string) []*protocol.Event {
+	found := make([]*protocol.Event, 0)
+	for _, event := range events {
+		if event.Resource == nil || event.Resource.Type != resourceType {
+			continue
+		}
+		if len(operation) == 0 || event.Resource.Operation == operation {
+			found = append(found, event)
+		}
+	}
+	return found
+}
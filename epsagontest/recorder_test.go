@@ -0,0 +1,106 @@
+package epsagontest_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/epsagon/epsagon-go/epsagon"
+	"github.com/epsagon/epsagon-go/epsagontest"
+	"github.com/epsagon/epsagon-go/protocol"
+	"github.com/epsagon/epsagon-go/tracer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestEpsagonTest(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Epsagon Test Helpers")
+}
+
+func newEvent(resourceType, operation string, metadata map[string]string) *protocol.Event {
+	return &protocol.Event{
+		Origin: "test",
+		Resource: &protocol.Resource{
+			Name:      "resource",
+			Type:      resourceType,
+			Operation: operation,
+			Metadata:  metadata,
+		},
+	}
+}
+
+var _ = Describe("Recorder", func() {
+	var recorder *epsagontest.Recorder
+
+	BeforeEach(func() {
+		recorder = epsagontest.NewRecorder(&tracer.Config{IgnoredKeys: []string{"password"}})
+	})
+
+	It("Records concurrently reported data", func() {
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				recorder.AddEvent(newEvent("http", "GET", map[string]string{}))
+				recorder.AddLabel(fmt.Sprintf("label-%d", i), i)
+				recorder.AddMetric(tracer.CounterMetric, "requests", 1, "")
+				recorder.AddExceptionTypeAndMessage("type", "message")
+			}(i)
+		}
+		wg.Wait()
+		Expect(recorder.Events()).To(HaveLen(50))
+		Expect(recorder.Labels()).To(HaveLen(50))
+		Expect(recorder.Metrics()).To(HaveLen(50))
+		Expect(recorder.Exceptions()).To(HaveLen(50))
+		Expect(recorder).To(epsagontest.HaveLabel("label-7", 7))
+		Expect(recorder).NotTo(epsagontest.HaveLabel("label-7", 8))
+	})
+
+	It("Keeps the errors given to AddError", func() {
+		epsagon.Error(errors.New("custom failure"), epsagon.ContextWithTracer(recorder))
+		exception := recorder.RunnerException()
+		Expect(exception).NotTo(BeNil())
+		Expect(exception.Type).To(Equal(epsagon.DefaultErrorType))
+		Expect(exception.Message).To(Equal("custom failure"))
+	})
+
+	It("Finds events by resource type and operation", func() {
+		recorder.AddEvent(newEvent("http", "GET", map[string]string{}))
+		recorder.AddEvent(newEvent("http", "POST", map[string]string{}))
+		recorder.AddEvent(newEvent("redis", "GET", map[string]string{}))
+		Expect(recorder.FindEvents("http", "")).To(HaveLen(2))
+		Expect(recorder.FindEvent("http", "POST").Resource.Operation).To(Equal("POST"))
+		Expect(recorder.FindEvent("dynamodb", "")).To(BeNil())
+	})
+
+	It("Masks the ignored keys when stopped", func() {
+		event := newEvent("http", "POST", map[string]string{
+			"request_body": `{"user": {"password": "1234"}}`,
+			"password":     "1234",
+			"status_code":  "500",
+		})
+		event.ErrorCode = protocol.ErrorCode_ERROR
+		recorder.AddEvent(event)
+		Expect(event).NotTo(epsagontest.HaveMaskedKey("password"))
+		recorder.Stop()
+		Expect(recorder.Stopped()).To(BeTrue())
+		Expect(event).To(epsagontest.HaveMaskedKey("password"))
+		Expect(event).NotTo(epsagontest.HaveMaskedKey("status_code"))
+		Expect(event).To(epsagontest.HaveErrorCode(protocol.ErrorCode_ERROR))
+		Expect(event.Resource.Metadata["request_body"]).To(MatchJSON(`{"user": {"password": "****"}}`))
+	})
+
+	It("Replaces the global tracer", func() {
+		previous := epsagontest.NewRecorder(nil)
+		tracer.GlobalTracer = previous
+		globalRecorder, restore := epsagontest.NewGlobalRecorder(nil)
+		epsagon.Label("key", "value")
+		Expect(globalRecorder).To(epsagontest.HaveLabel("key", "value"))
+		restore()
+		Expect(tracer.GlobalTracer).To(BeIdenticalTo(previous))
+		tracer.GlobalTracer = nil
+	})
+})
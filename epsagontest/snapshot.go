@@ -0,0 +1,168 @@
+package epsagontest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/epsagon/epsagon-go/protocol"
+	"github.com/epsagon/epsagon-go/tracer"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+)
+
+// UpdateSnapshotsEnvVar is the environment variable that, when set to TRUE,
+// makes AssertTraceSnapshot write the snapshots instead of comparing them
+const UpdateSnapshotsEnvVar = "EPSAGON_UPDATE_SNAPSHOTS"
+
+// TestingT is the subset of testing.TB used by AssertTraceSnapshot,
+// satisfied by *testing.T and GinkgoT()
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// runtimeMetricsPrefix is the prefix of the runtime metrics keys in the runner metadata
+const runtimeMetricsPrefix = "runtime."
+
+// volatileExceptionKeys are the exception additional data keys that depend on
+// the time or on the source lines of the stack trace
+var volatileExceptionKeys = []string{
+	tracer.FramesKey,
+	tracer.FingerprintKey,
+	tracer.FirstTimestampKey,
+	tracer.LastTimestampKey,
+}
+
+// NormalizeTrace returns a copy of the trace without its volatile fields:
+// event start times and durations, captured log times, runtime metrics,
+// exception times, tracebacks, stack frames and fingerprints, and the library
+// version and platform
+func NormalizeTrace(trace *protocol.Trace) *protocol.Trace {
+	normalized := proto.Clone(trace).(*protocol.Trace)
+	normalized.Version = ""
+	normalized.Platform = ""
+	for _, event := range normalized.Events {
+		event.StartTime = 0
+		event.Duration = 0
+		if event.Resource != nil {
+			normalizeMetadata(event.Resource.Metadata)
+		}
+		normalizeException(event.Exception)
+	}
+	for _, exception := range normalized.Exceptions {
+		normalizeException(exception)
+	}
+	return normalized
+}
+
+func normalizeMetadata(metadata map[string]string) {
+	for key := range metadata {
+		if strings.HasPrefix(key, runtimeMetricsPrefix) {
+			delete(metadata, key)
+		}
+	}
+	encodedLogs, ok := metadata[tracer.LogsKey]
+	if !ok {
+		return
+	}
+	var logs []tracer.LogRecord
+	if err := json.Unmarshal([]byte(encodedLogs), &logs); err != nil {
+		return
+	}
+	for i := range logs {
+		logs[i].Time = 0
+	}
+	if encoded, err := json.Marshal(logs); err == nil {
+		metadata[tracer.LogsKey] = string(encoded)
+	}
+}
+
+func normalizeException(exception *protocol.Exception) {
+	if exception == nil {
+		return
+	}
+	exception.Time = 0
+	exception.Traceback = ""
+	for _, key := range volatileExceptionKeys {
+		if _, ok := exception.AdditionalData[key]; ok {
+			exception.AdditionalData[key] = ""
+		}
+	}
+}
+
+// MarshalTraceSnapshot returns the normalized trace as indented JSON, the
+// format of the snapshot files
+func MarshalTraceSnapshot(trace *protocol.Trace) ([]byte, error) {
+	marshaler := jsonpb.Marshaler{OrigName: true, Indent: "  "}
+	snapshot, err := marshaler.MarshalToString(NormalizeTrace(trace))
+	if err != nil {
+		return nil, err
+	}
+	return []byte(snapshot + "\n"), nil
+}
+
+// AssertTraceSnapshot compares the normalized trace to the snapshot file at
+// path and reports the first difference. Run the tests with
+// EPSAGON_UPDATE_SNAPSHOTS=TRUE to write the snapshots instead. Returns
+// whether the trace matches the snapshot
+func AssertTraceSnapshot(t TestingT, trace *protocol.Trace, path string) bool {
+	t.Helper()
+	actual, err := MarshalTraceSnapshot(trace)
+	if err != nil {
+		t.Errorf("failed to marshal trace: %v", err)
+		return false
+	}
+	if shouldUpdateSnapshots() {
+		if err := writeSnapshot(path, actual); err != nil {
+			t.Errorf("failed to write snapshot %s: %v", path, err)
+			return false
+		}
+		return true
+	}
+	expected, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Errorf("failed to read snapshot %s, run with %s=TRUE to create it: %v", path, UpdateSnapshotsEnvVar, err)
+		return false
+	}
+	if bytes.Equal(expected, actual) {
+		return true
+	}
+	t.Errorf("trace does not match snapshot %s, run with %s=TRUE to update it\n%s",
+		path, UpdateSnapshotsEnvVar, snapshotDifference(string(expected), string(actual)))
+	return false
+}
+
+func shouldUpdateSnapshots() bool {
+	return strings.ToUpper(os.Getenv(UpdateSnapshotsEnvVar)) == "TRUE"
+}
+
+func writeSnapshot(path string, snapshot []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, snapshot, 0644)
+}
+
+// snapshotDifference describes the first line that differs between the snapshots
+func snapshotDifference(expected, actual string) string {
+	expectedLines := strings.Split(expected, "\n")
+	actualLines := strings.Split(actual, "\n")
+	for i := 0; i < len(expectedLines) || i < len(actualLines); i++ {
+		expectedLine, actualLine := "<missing>", "<missing>"
+		if i < len(expectedLines) {
+			expectedLine = expectedLines[i]
+		}
+		if i < len(actualLines) {
+			actualLine = actualLines[i]
+		}
+		if expectedLine != actualLine {
+			return fmt.Sprintf("line %d:\n  expected: %s\n  actual:   %s", i+1, expectedLine, actualLine)
+		}
+	}
+	return ""
+}
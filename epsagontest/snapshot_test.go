@@ -0,0 +1,196 @@
+package epsagontest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/epsagon/epsagon-go/epsagon"
+	"github.com/epsagon/epsagon-go/epsagontest"
+	"github.com/epsagon/epsagon-go/protocol"
+	"github.com/epsagon/epsagon-go/tracer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type recordingT struct {
+	errors []string
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func newSnapshotTrace(startTime float64, traceback string) *protocol.Trace {
+	return &protocol.Trace{
+		AppName:  "snapshot-app",
+		Version:  "1.0.0",
+		Platform: "go test",
+		Events: []*protocol.Event{{
+			Id:        "event-id",
+			Origin:    "runner",
+			StartTime: startTime,
+			Duration:  startTime / 2,
+			ErrorCode: protocol.ErrorCode_EXCEPTION,
+			Resource: &protocol.Resource{
+				Name:      "handler",
+				Type:      "go-function",
+				Operation: "invoke",
+				Metadata:  map[string]string{"key": "value"},
+			},
+			Exception: &protocol.Exception{
+				Type:      "panic",
+				Message:   "failed",
+				Traceback: traceback,
+				Time:      startTime,
+			},
+		}},
+	}
+}
+
+// tracedExceptionTrace sends the trace of a wrapped function that logs a line
+// and reports an exception, created on a different source line by each variant
+func tracedExceptionTrace(start time.Time, variant int) *protocol.Trace {
+	collector := epsagontest.NewFakeCollector("test-token")
+	defer collector.Close()
+	config := epsagontest.Deterministic(collector.TracerConfig("snapshot-app"), start)
+	config.RuntimeMetrics = true
+	epsagon.ConcurrentGoWrapper(
+		&epsagon.Config{Config: *config},
+		func(ctx context.Context) {
+			currentTracer := epsagon.ExtractTracer([]context.Context{ctx})
+			tracer.AddTracerLog(currentTracer, tracer.NewLogRecord(time.Now(), "info", "handling"))
+			var exception *protocol.Exception
+			if variant == 1 {
+				exception = tracer.NewException("handler error", "failed", errors.New("failed"))
+			} else {
+				exception = tracer.NewException("handler error", "failed", errors.New("failed"))
+			}
+			currentTracer.AddException(exception)
+		},
+		"snapshot-handler",
+	)()
+	trace, err := collector.WaitForTrace(time.Second)
+	Expect(err).To(BeNil())
+	return trace
+}
+
+var _ = Describe("AssertTraceSnapshot", func() {
+	var (
+		snapshotDir string
+		t           *recordingT
+	)
+
+	BeforeEach(func() {
+		var err error
+		snapshotDir, err = ioutil.TempDir("", "snapshots")
+		Expect(err).To(BeNil())
+		t = &recordingT{}
+	})
+
+	AfterEach(func() {
+		os.Unsetenv(epsagontest.UpdateSnapshotsEnvVar)
+		os.RemoveAll(snapshotDir)
+	})
+
+	It("Matches the checked-in snapshot of a wrapped function", func() {
+		collector := epsagontest.NewFakeCollector("test-token")
+		defer collector.Close()
+		config := epsagontest.Deterministic(
+			collector.TracerConfig("snapshot-app"), time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC))
+		epsagon.ConcurrentGoWrapper(
+			&epsagon.Config{Config: *config},
+			func(ctx context.Context) {
+				epsagon.Label("user", "test", ctx)
+			},
+			"snapshot-handler",
+		)()
+		trace, err := collector.WaitForTrace(time.Second)
+		Expect(err).To(BeNil())
+		Expect(epsagontest.AssertTraceSnapshot(GinkgoT(), trace, "testdata/go_wrapper.golden.json")).To(BeTrue())
+	})
+
+	It("Ignores timestamps, durations, tracebacks and versions", func() {
+		path := filepath.Join(snapshotDir, "trace.golden.json")
+		os.Setenv(epsagontest.UpdateSnapshotsEnvVar, "TRUE")
+		Expect(epsagontest.AssertTraceSnapshot(t, newSnapshotTrace(1, "first"), path)).To(BeTrue())
+		os.Unsetenv(epsagontest.UpdateSnapshotsEnvVar)
+
+		different := newSnapshotTrace(2, "second")
+		different.Version = "2.0.0"
+		different.Platform = "go other"
+		Expect(epsagontest.AssertTraceSnapshot(t, different, path)).To(BeTrue())
+		Expect(t.errors).To(BeEmpty())
+	})
+
+	It("Ignores stack frames, fingerprints, log times and runtime metrics", func() {
+		path := filepath.Join(snapshotDir, "exception.golden.json")
+		first := tracedExceptionTrace(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC), 1)
+		Expect(first.Exceptions).To(HaveLen(1))
+		Expect(first.Exceptions[0].AdditionalData[tracer.FramesKey]).NotTo(BeEmpty())
+		Expect(first.Events[0].Resource.Metadata).To(HaveKey(tracer.GoroutinesKey))
+		os.Setenv(epsagontest.UpdateSnapshotsEnvVar, "TRUE")
+		Expect(epsagontest.AssertTraceSnapshot(t, first, path)).To(BeTrue())
+		os.Unsetenv(epsagontest.UpdateSnapshotsEnvVar)
+
+		second := tracedExceptionTrace(time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC), 2)
+		Expect(second.Exceptions[0].AdditionalData[tracer.FingerprintKey]).NotTo(
+			Equal(first.Exceptions[0].AdditionalData[tracer.FingerprintKey]))
+		Expect(epsagontest.AssertTraceSnapshot(t, second, path)).To(BeTrue())
+		Expect(t.errors).To(BeEmpty())
+
+		snapshot, err := ioutil.ReadFile(path)
+		Expect(err).To(BeNil())
+		Expect(string(snapshot)).To(ContainSubstring("error_chain"))
+		Expect(string(snapshot)).To(ContainSubstring("handling"))
+		Expect(string(snapshot)).NotTo(ContainSubstring(tracer.GoroutinesKey))
+	})
+
+	It("Reports the first differing line", func() {
+		path := filepath.Join(snapshotDir, "trace.golden.json")
+		os.Setenv(epsagontest.UpdateSnapshotsEnvVar, "TRUE")
+		epsagontest.AssertTraceSnapshot(t, newSnapshotTrace(1, ""), path)
+		os.Unsetenv(epsagontest.UpdateSnapshotsEnvVar)
+
+		different := newSnapshotTrace(1, "")
+		different.Events[0].Resource.Metadata["key"] = "other"
+		Expect(epsagontest.AssertTraceSnapshot(t, different, path)).To(BeFalse())
+		Expect(t.errors).To(HaveLen(1))
+		Expect(t.errors[0]).To(ContainSubstring(`"key": "value"`))
+		Expect(t.errors[0]).To(ContainSubstring(`"key": "other"`))
+	})
+
+	It("Fails when the snapshot is missing", func() {
+		path := filepath.Join(snapshotDir, "missing.golden.json")
+		Expect(epsagontest.AssertTraceSnapshot(t, newSnapshotTrace(1, ""), path)).To(BeFalse())
+		Expect(t.errors).To(HaveLen(1))
+		Expect(t.errors[0]).To(ContainSubstring(epsagontest.UpdateSnapshotsEnvVar))
+	})
+
+	It("Snapshots the trace of a Recorder", func() {
+		path := filepath.Join(snapshotDir, "recorder.golden.json")
+		recorder := epsagontest.NewRecorder(nil)
+		recorder.AddEvent(newSnapshotTrace(1, "").Events[0])
+		os.Setenv(epsagontest.UpdateSnapshotsEnvVar, "TRUE")
+		Expect(epsagontest.AssertTraceSnapshot(t, recorder.Trace(), path)).To(BeTrue())
+		os.Unsetenv(epsagontest.UpdateSnapshotsEnvVar)
+
+		trace := newSnapshotTrace(2, "")
+		trace.AppName = ""
+		Expect(epsagontest.AssertTraceSnapshot(t, trace, path)).To(BeTrue())
+		Expect(t.errors).To(BeEmpty())
+	})
+
+	It("Does not modify the given trace", func() {
+		trace := newSnapshotTrace(1, "traceback")
+		epsagontest.NormalizeTrace(trace)
+		Expect(trace.Events[0].StartTime).To(Equal(1.0))
+		Expect(trace.Events[0].Exception.Traceback).To(Equal("traceback"))
+	})
+})
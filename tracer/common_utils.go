@@ -2,9 +2,83 @@ package tracer
 
 import (
 	"time"
+
+	"github.com/google/uuid"
 )
 
-// GetTimestamp returns the current time in miliseconds
+// Clock returns the current time, set Config.Clock to control the timestamps of the traced events
+type Clock func() time.Time
+
+// IDGenerator returns a new unique identifier, set Config.IDGenerator to
+// control the identifiers of the traces and traced events
+type IDGenerator func() string
+
+// Timestamp converts a time to the events timestamp format, seconds since the epoch
+func Timestamp(t time.Time) float64 {
+	return float64(t.UnixNano()) / float64(time.Millisecond) / float64(time.Nanosecond) / 1000.0
+}
+
+// GetTimestamp returns the current time in seconds, from the clock of the global tracer if it has one
 func GetTimestamp() float64 {
-	return float64(time.Now().UnixNano()) / float64(time.Millisecond) / float64(time.Nanosecond) / 1000.0
+	return globalTracerConfig().GetTimestamp()
+}
+
+// NewID returns a new unique identifier, from the ID generator of the global tracer if it has one
+func NewID() string {
+	return globalTracerConfig().NewID()
+}
+
+func globalTracerConfig() *Config {
+	if GlobalTracer == nil || GlobalTracer.Stopped() {
+		return nil
+	}
+	return GlobalTracer.GetConfig()
+}
+
+// GetTimestamp returns the current time in seconds from the config Clock, or the system clock if not set
+func (config *Config) GetTimestamp() float64 {
+	if config != nil && config.Clock != nil {
+		return Timestamp(config.Clock())
+	}
+	return Timestamp(time.Now())
+}
+
+// NewID returns a new identifier from the config IDGenerator, or a random UUID if not set
+func (config *Config) NewID() string {
+	if config != nil && config.IDGenerator != nil {
+		return config.IDGenerator()
+	}
+	return uuid.New().String()
+}
+
+// GetTracerTimestamp returns the current time in seconds from the clock of the given tracer
+func GetTracerTimestamp(currentTracer Tracer) float64 {
+	if currentTracer == nil {
+		return GetTimestamp()
+	}
+	return currentTracer.GetConfig().GetTimestamp()
+}
+
+// TraceIDTracer is implemented by tracers that identify the trace they collect,
+// it is not part of Tracer so that other Tracer implementations are not required to
+type TraceIDTracer interface {
+	// GetTraceID Returns the identifier of the collected trace
+	GetTraceID() string
+}
+
+// GetTracerTraceID returns the identifier of the trace the given tracer collects,
+// or an empty string if the tracer does not implement TraceIDTracer
+func GetTracerTraceID(currentTracer Tracer) string {
+	if traceIDTracer, ok := currentTracer.(TraceIDTracer); ok {
+		return traceIDTracer.GetTraceID()
+	}
+	return ""
+}
+
+// NewTracerID returns a new identifier from the ID generator of the given tracer
+func NewTracerID(currentTracer Tracer) string {
+	if currentTracer == nil {
+		return NewID()
+	}
+	return currentTracer.GetConfig().NewID()
 }
@@ -0,0 +1,253 @@
+package tracer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultConfigWatchInterval is the default interval between checks of a watched config file
+const DefaultConfigWatchInterval = 5 * time.Second
+
+// ConfigOverrides are the options of a watched config file. They override
+// the options of every tracer created while the file is watched, options
+// missing from the file are left unchanged. Traces are not sampled by the
+// tracer, so there is no sampling option to override
+type ConfigOverrides struct {
+	MetadataOnly    *bool     `json:"metadata_only,omitempty"`
+	Disable         *bool     `json:"disable,omitempty"`
+	Debug           *bool     `json:"debug,omitempty"`
+	IgnoredKeys     *[]string `json:"ignored_keys,omitempty"`
+	SendTimeout     *string   `json:"send_timeout,omitempty"`
+	MaxTraceSize    *int      `json:"max_trace_size,omitempty"`
+	MaxCapturedLogs *int      `json:"max_captured_logs,omitempty"`
+	RuntimeMetrics  *bool     `json:"runtime_metrics,omitempty"`
+}
+
+// Validate returns an error if one of the overridden options is invalid
+func (overrides *ConfigOverrides) Validate() error {
+	if overrides.SendTimeout != nil {
+		if _, err := time.ParseDuration(*overrides.SendTimeout); err != nil {
+			return fmt.Errorf("invalid send_timeout: %v", err)
+		}
+	}
+	if overrides.MaxTraceSize != nil && (*overrides.MaxTraceSize <= 0 || *overrides.MaxTraceSize > MaxTraceSize) {
+		return fmt.Errorf("invalid max_trace_size %d, must be between 1 and %d", *overrides.MaxTraceSize, MaxTraceSize)
+	}
+	if overrides.MaxCapturedLogs != nil && *overrides.MaxCapturedLogs <= 0 {
+		return fmt.Errorf("invalid max_captured_logs %d, must be positive", *overrides.MaxCapturedLogs)
+	}
+	return nil
+}
+
+// apply returns a copy of config with the overridden options
+func (overrides *ConfigOverrides) apply(config *Config) *Config {
+	overridden := copyConfig(config)
+	if overrides.MetadataOnly != nil {
+		overridden.MetadataOnly = *overrides.MetadataOnly
+	}
+	if overrides.Disable != nil {
+		overridden.Disable = *overrides.Disable
+	}
+	if overrides.Debug != nil {
+		overridden.Debug = *overrides.Debug
+	}
+	if overrides.IgnoredKeys != nil {
+		overridden.IgnoredKeys = append([]string{}, *overrides.IgnoredKeys...)
+	}
+	if overrides.SendTimeout != nil {
+		overridden.SendTimeout = *overrides.SendTimeout
+	}
+	if overrides.MaxTraceSize != nil {
+		overridden.MaxTraceSize = *overrides.MaxTraceSize
+	}
+	if overrides.MaxCapturedLogs != nil {
+		overridden.MaxCapturedLogs = *overrides.MaxCapturedLogs
+	}
+	if overrides.RuntimeMetrics != nil {
+		overridden.RuntimeMetrics = *overrides.RuntimeMetrics
+	}
+	return overridden
+}
+
+// ParseConfigOverrides parses and validates the JSON content of a config file
+func ParseConfigOverrides(data []byte) (*ConfigOverrides, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	overrides := &ConfigOverrides{}
+	if err := decoder.Decode(overrides); err != nil {
+		return nil, fmt.Errorf("invalid config file: %v", err)
+	}
+	if err := overrides.Validate(); err != nil {
+		return nil, err
+	}
+	return overrides, nil
+}
+
+var activeOverrides atomic.Value
+
+type overridesHolder struct {
+	overrides *ConfigOverrides
+}
+
+// applyConfigOverrides returns the config a new tracer should use,
+// a copy of config with the watched overrides if a config file is watched
+func applyConfigOverrides(config *Config) *Config {
+	holder, _ := activeOverrides.Load().(overridesHolder)
+	if holder.overrides == nil {
+		return config
+	}
+	return holder.overrides.apply(config)
+}
+
+// CurrentConfigOverrides returns the overrides of the watched config file, or nil if none
+func CurrentConfigOverrides() *ConfigOverrides {
+	holder, _ := activeOverrides.Load().(overridesHolder)
+	return holder.overrides
+}
+
+// ConfigWatcher watches a local JSON config file, for example a mounted
+// ConfigMap, and atomically swaps the options used by new tracers when the
+// file changes. Tracers that are already running keep their options
+type ConfigWatcher struct {
+	path     string
+	interval time.Duration
+	lock     sync.Mutex
+	content  []byte
+	// invalidContent and invalidErr are the last content that failed to
+	// parse and its error, so an unchanged invalid file isn't parsed again
+	invalidContent []byte
+	invalidErr     error
+	stop           chan struct{}
+	stopOnce       sync.Once
+	done           chan struct{}
+}
+
+// WatchConfigFile loads the config file at path and checks it for changes
+// every interval. Only one file is watched at a time, watching a new file
+// stops the previous watcher once the new file is loaded, a file that fails
+// to load leaves the previous watcher running. Once watched, changes to an
+// invalid file are logged and ignored, keeping the last valid options
+func WatchConfigFile(path string, interval time.Duration) (*ConfigWatcher, error) {
+	if interval <= 0 {
+		interval = DefaultConfigWatchInterval
+	}
+	content, err := readConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := ParseConfigOverrides(content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	watcher := &ConfigWatcher{
+		path:     path,
+		interval: interval,
+		content:  content,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	watchersLock.Lock()
+	defer watchersLock.Unlock()
+	if currentWatcher != nil {
+		currentWatcher.stopWatching()
+	}
+	activeOverrides.Store(overridesHolder{overrides: overrides})
+	DefaultLogger(false).Warnf("configuration overridden by %s", path)
+	currentWatcher = watcher
+	go watcher.run()
+	return watcher, nil
+}
+
+var (
+	watchersLock   sync.Mutex
+	currentWatcher *ConfigWatcher
+)
+
+func readConfigFile(path string) ([]byte, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %v", path, err)
+	}
+	return content, nil
+}
+
+// Reload reads the config file and swaps the overrides if it changed
+func (watcher *ConfigWatcher) Reload() error {
+	watcher.lock.Lock()
+	defer watcher.lock.Unlock()
+	content, err := readConfigFile(watcher.path)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(content, watcher.content) {
+		return nil
+	}
+	if watcher.invalidContent != nil && bytes.Equal(content, watcher.invalidContent) {
+		return watcher.invalidErr
+	}
+	overrides, err := ParseConfigOverrides(content)
+	if err != nil {
+		watcher.invalidContent = content
+		watcher.invalidErr = fmt.Errorf("%s: %v", watcher.path, err)
+		return watcher.invalidErr
+	}
+	select {
+	case <-watcher.stop:
+		return nil
+	default:
+	}
+	watcher.content = content
+	watcher.invalidContent = nil
+	watcher.invalidErr = nil
+	activeOverrides.Store(overridesHolder{overrides: overrides})
+	DefaultLogger(false).Warnf("configuration overridden by %s", watcher.path)
+	return nil
+}
+
+// Stop stops watching the config file and removes its overrides
+func (watcher *ConfigWatcher) Stop() {
+	watchersLock.Lock()
+	defer watchersLock.Unlock()
+	watcher.stopWatching()
+	if currentWatcher == watcher {
+		currentWatcher = nil
+		activeOverrides.Store(overridesHolder{})
+	}
+}
+
+func (watcher *ConfigWatcher) stopWatching() {
+	watcher.stopOnce.Do(func() {
+		watcher.lock.Lock()
+		defer watcher.lock.Unlock()
+		close(watcher.stop)
+	})
+	<-watcher.done
+}
+
+// run reloads the config file every interval, an error is logged once
+// until the file changes
+func (watcher *ConfigWatcher) run() {
+	defer close(watcher.done)
+	ticker := time.NewTicker(watcher.interval)
+	defer ticker.Stop()
+	lastError := ""
+	for {
+		select {
+		case <-ticker.C:
+			err := watcher.Reload()
+			if err == nil {
+				lastError = ""
+			} else if err.Error() != lastError {
+				lastError = err.Error()
+				DefaultLogger(false).Errorf("failed to reload configuration: %v", err)
+			}
+		case <-watcher.stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package tracer_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/epsagon/epsagon-go/tracer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ConfigWatcher", func() {
+	var (
+		dir     string
+		path    string
+		watcher *tracer.ConfigWatcher
+	)
+
+	writeConfig := func(content string) {
+		Expect(ioutil.WriteFile(path, []byte(content), 0644)).To(Succeed())
+	}
+
+	tracerConfig := func() *tracer.Config {
+		config := &tracer.Config{Token: "token", MetadataOnly: true, IgnoredKeys: []string{"password"}}
+		return tracer.CreateTracer(config).GetConfig()
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "epsagon-config")
+		Expect(err).To(BeNil())
+		path = filepath.Join(dir, "epsagon.json")
+	})
+
+	AfterEach(func() {
+		if watcher != nil {
+			watcher.Stop()
+			watcher = nil
+		}
+		os.RemoveAll(dir)
+	})
+
+	It("Overrides the config of new tracers", func() {
+		writeConfig(`{"metadata_only": false, "ignored_keys": ["card"]}`)
+		var err error
+		watcher, err = tracer.WatchConfigFile(path, 10*time.Millisecond)
+		Expect(err).To(BeNil())
+		config := tracerConfig()
+		Expect(config.MetadataOnly).To(BeFalse())
+		Expect(config.IgnoredKeys).To(Equal([]string{"card"}))
+		Expect(config.Token).To(Equal("token"))
+
+		writeConfig(`{"disable": true}`)
+		Eventually(func() bool { return tracerConfig().Disable }).Should(BeTrue())
+		Expect(config.Disable).To(BeFalse())
+		Expect(tracerConfig().MetadataOnly).To(BeTrue())
+
+		watcher.Stop()
+		Expect(tracer.CurrentConfigOverrides()).To(BeNil())
+		Expect(tracerConfig().Disable).To(BeFalse())
+	})
+
+	It("Keeps the last valid config when the file is invalid", func() {
+		writeConfig(`{"metadata_only": false}`)
+		var err error
+		watcher, err = tracer.WatchConfigFile(path, 10*time.Millisecond)
+		Expect(err).To(BeNil())
+		writeConfig(`{"send_timeout": "soon"}`)
+		Consistently(func() bool { return tracerConfig().MetadataOnly }, 50*time.Millisecond).Should(BeFalse())
+		Expect(watcher.Reload()).To(MatchError(ContainSubstring("invalid send_timeout")))
+	})
+
+	It("Logs reloads and an invalid file once", func() {
+		logger := &recordingLogger{}
+		tracer.SetDefaultLogger(logger)
+		defer tracer.SetDefaultLogger(nil)
+		writeConfig(`{"metadata_only": false}`)
+		var err error
+		watcher, err = tracer.WatchConfigFile(path, 5*time.Millisecond)
+		Expect(err).To(BeNil())
+		Expect(logger.Messages()).To(ConsistOf("warn configuration overridden by " + path))
+
+		writeConfig(`{"send_timeout": "soon"}`)
+		Eventually(logger.Messages).Should(HaveLen(2))
+		Consistently(logger.Messages, 50*time.Millisecond).Should(HaveLen(2))
+		Expect(logger.Messages()[1]).To(HavePrefix("error failed to reload configuration"))
+		Expect(logger.Messages()[1]).To(ContainSubstring("invalid send_timeout"))
+
+		writeConfig(`{"metadata_only": true}`)
+		Eventually(logger.Messages).Should(HaveLen(3))
+		Expect(logger.Messages()[2]).To(Equal("warn configuration overridden by " + path))
+	})
+
+	It("Keeps the previous watcher when a new file fails to load", func() {
+		writeConfig(`{"metadata_only": false}`)
+		var err error
+		watcher, err = tracer.WatchConfigFile(path, 10*time.Millisecond)
+		Expect(err).To(BeNil())
+		invalidPath := filepath.Join(dir, "invalid.json")
+		Expect(ioutil.WriteFile(invalidPath, []byte(`{"unknown_option": true}`), 0644)).To(Succeed())
+		_, err = tracer.WatchConfigFile(invalidPath, 10*time.Millisecond)
+		Expect(err).NotTo(BeNil())
+		Expect(tracerConfig().MetadataOnly).To(BeFalse())
+
+		writeConfig(`{"disable": true}`)
+		Eventually(func() bool { return tracerConfig().Disable }).Should(BeTrue())
+	})
+
+	It("Fails to watch an invalid file", func() {
+		writeConfig(`{"unknown_option": true}`)
+		_, err := tracer.WatchConfigFile(path, time.Second)
+		Expect(err).NotTo(BeNil())
+		_, err = tracer.WatchConfigFile(filepath.Join(dir, "missing.json"), time.Second)
+		Expect(err).NotTo(BeNil())
+		Expect(tracer.CurrentConfigOverrides()).To(BeNil())
+	})
+})
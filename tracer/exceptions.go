@@ -0,0 +1,262 @@
+package tracer
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/epsagon/epsagon-go/protocol"
+)
+
+// FramesKey is the key for the parsed stack frames in exception additional data
+const FramesKey = "frames"
+
+// ErrorChainKey is the key for the wrapped error chain in exception additional data
+const ErrorChainKey = "error_chain"
+
+// MaxStackFrames is the maximum number of stack frames kept per exception
+const MaxStackFrames = 64
+
+// FingerprintKey is the key for the grouping fingerprint in exception additional data
+const FingerprintKey = "fingerprint"
+
+// ExceptionCountKey is the key for the number of grouped occurrences in exception additional data
+const ExceptionCountKey = "count"
+
+// FirstTimestampKey is the key for the time of the first grouped occurrence in exception additional data
+const FirstTimestampKey = "first_timestamp"
+
+// LastTimestampKey is the key for the time of the last grouped occurrence in exception additional data
+const LastTimestampKey = "last_timestamp"
+
+// DroppedExceptionsKey is the key for the number of exceptions dropped by the per trace cap in resource metadata
+const DroppedExceptionsKey = "dropped_exceptions"
+
+// DefaultMaxExceptions is the default number of distinct exceptions kept per trace
+const DefaultMaxExceptions = 20
+
+// fingerprintFramesCount is the number of top stack frames used in the fingerprint
+const fingerprintFramesCount = 5
+
+// messageVariablesRegex matches the variable parts of exception messages
+// (uuids, hex and decimal numbers) that are ignored by the fingerprint
+var messageVariablesRegex = regexp.MustCompile(
+	`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|0x[0-9a-fA-F]+|[0-9]+`)
+
+// maxErrorChainLength protects against errors that unwrap to themselves
+const maxErrorChainLength = 32
+
+const epsagonPackagePrefix = "github.com/epsagon/epsagon-go/"
+
+// StackFrame is a parsed frame of an exception stack trace
+type StackFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	InApp    bool   `json:"in_app"`
+}
+
+// ChainedError is an error of a wrapped error chain
+type ChainedError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// NewException creates an exception with the current time and the stack trace
+// of its caller. If value is an error, its wrapped error chain is added and
+// the stack trace it carries (github.com/pkg/errors style) is preferred
+func NewException(exceptionType, message string, value interface{}) *protocol.Exception {
+	return newException(GetTimestamp(), exceptionType, message, value)
+}
+
+// NewTracerException creates an exception like NewException, with the
+// current time from the clock of the given tracer
+func NewTracerException(currentTracer Tracer, exceptionType, message string, value interface{}) *protocol.Exception {
+	return newException(GetTracerTimestamp(currentTracer), exceptionType, message, value)
+}
+
+// newException must be called directly by the exported constructors,
+// the stack trace starts at their caller
+func newException(timestamp float64, exceptionType, message string, value interface{}) *protocol.Exception {
+	pcs := make([]uintptr, MaxStackFrames)
+	pcs = pcs[:runtime.Callers(3, pcs)]
+	frames := parseStackFrames(pcs)
+	exception := &protocol.Exception{
+		Type:           exceptionType,
+		Message:        message,
+		Traceback:      formatStackFrames(frames),
+		Time:           timestamp,
+		AdditionalData: map[string]string{},
+	}
+	setExceptionFrames(exception, frames)
+	if err, ok := value.(error); ok {
+		AddErrorDetails(exception, err)
+	}
+	return exception
+}
+
+// AddErrorDetails adds the wrapped error chain of err to the exception.
+// If an error of the chain carries a stack trace, the deepest one replaces
+// the exception traceback and frames
+func AddErrorDetails(exception *protocol.Exception, err error) {
+	if err == nil {
+		return
+	}
+	if exception.AdditionalData == nil {
+		exception.AdditionalData = map[string]string{}
+	}
+	chain := make([]ChainedError, 0)
+	var errorStack []uintptr
+	for current := err; current != nil && len(chain) < maxErrorChainLength; current = unwrapError(current) {
+		chain = append(chain, ChainedError{
+			Type:    fmt.Sprintf("%T", current),
+			Message: current.Error(),
+		})
+		if pcs := extractErrorStack(current); len(pcs) > 0 {
+			errorStack = pcs
+		}
+	}
+	if encoded, encodeErr := json.Marshal(chain); encodeErr == nil {
+		exception.AdditionalData[ErrorChainKey] = string(encoded)
+	}
+	if len(errorStack) > 0 {
+		frames := parseStackFrames(errorStack)
+		exception.Traceback = formatStackFrames(frames)
+		setExceptionFrames(exception, frames)
+	}
+}
+
+func unwrapError(err error) error {
+	if unwrapped := errors.Unwrap(err); unwrapped != nil {
+		return unwrapped
+	}
+	if causer, ok := err.(interface{ Cause() error }); ok {
+		return causer.Cause()
+	}
+	return nil
+}
+
+// extractErrorStack returns the program counters of a StackTrace() method
+// returning a slice of uintptr based frames, as github.com/pkg/errors does
+func extractErrorStack(err error) []uintptr {
+	method := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+		return nil
+	}
+	stackType := method.Type().Out(0)
+	if stackType.Kind() != reflect.Slice || stackType.Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+	stack := method.Call(nil)[0]
+	pcs := make([]uintptr, 0, stack.Len())
+	for i := 0; i < stack.Len() && i < MaxStackFrames; i++ {
+		pcs = append(pcs, uintptr(stack.Index(i).Uint()))
+	}
+	return pcs
+}
+
+func parseStackFrames(pcs []uintptr) []StackFrame {
+	frames := make([]StackFrame, 0, len(pcs))
+	if len(pcs) == 0 {
+		return frames
+	}
+	callersFrames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := callersFrames.Next()
+		frames = append(frames, StackFrame{
+			Function: frame.Function,
+			File:     frame.File,
+			Line:     frame.Line,
+			InApp:    isInAppFrame(frame.Function, frame.File),
+		})
+		if !more || len(frames) == MaxStackFrames {
+			return frames
+		}
+	}
+}
+
+func isInAppFrame(function, file string) bool {
+	if function == "" || strings.HasPrefix(function, epsagonPackagePrefix) {
+		return false
+	}
+	if goroot := runtime.GOROOT(); goroot != "" && strings.HasPrefix(file, goroot) {
+		return false
+	}
+	if strings.Contains(file, "/pkg/mod/") || strings.Contains(file, "/vendor/") {
+		return false
+	}
+	// top level standard library packages (runtime, reflect...) of -trimpath builds
+	if !strings.Contains(function, "/") && !strings.HasPrefix(function, "main.") {
+		return false
+	}
+	return true
+}
+
+func formatStackFrames(frames []StackFrame) string {
+	var builder strings.Builder
+	for _, frame := range frames {
+		fmt.Fprintf(&builder, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+	}
+	return builder.String()
+}
+
+func setExceptionFrames(exception *protocol.Exception, frames []StackFrame) {
+	if encoded, err := json.Marshal(frames); err == nil {
+		exception.AdditionalData[FramesKey] = string(encoded)
+	}
+}
+
+// ExceptionFingerprint returns the grouping fingerprint of an exception,
+// computed from its type, message template and top stack frames
+func ExceptionFingerprint(exception *protocol.Exception) string {
+	hash := sha1.New()
+	fmt.Fprintf(hash, "%s\n%s\n", exception.Type, messageVariablesRegex.ReplaceAllString(exception.Message, "*"))
+	var frames []StackFrame
+	if encoded, ok := exception.AdditionalData[FramesKey]; ok && json.Unmarshal([]byte(encoded), &frames) == nil {
+		for i := 0; i < len(frames) && i < fingerprintFramesCount; i++ {
+			fmt.Fprintf(hash, "%s:%d\n", frames[i].Function, frames[i].Line)
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// recordException groups the exception with previous exceptions of the same
+// fingerprint, new exceptions beyond Config.MaxExceptions are dropped
+func (tracer *epsagonTracer) recordException(exception *protocol.Exception) {
+	if exception.AdditionalData == nil {
+		exception.AdditionalData = map[string]string{}
+	}
+	fingerprint := ExceptionFingerprint(exception)
+	if group, ok := tracer.exceptionGroups[fingerprint]; ok {
+		count, _ := strconv.Atoi(group.AdditionalData[ExceptionCountKey])
+		group.AdditionalData[ExceptionCountKey] = strconv.Itoa(count + 1)
+		group.AdditionalData[LastTimestampKey] = strconv.FormatFloat(exception.Time, 'f', -1, 64)
+		return
+	}
+	if len(tracer.exceptions) >= tracer.Config.MaxExceptions {
+		tracer.droppedExceptions++
+		if tracer.Config.Debug {
+			tracer.Config.GetLogger().Debugf("dropping exception, too many exceptions in trace: %s", exception.Message)
+		}
+		return
+	}
+	exception.AdditionalData[FingerprintKey] = fingerprint
+	exception.AdditionalData[ExceptionCountKey] = "1"
+	exception.AdditionalData[FirstTimestampKey] = strconv.FormatFloat(exception.Time, 'f', -1, 64)
+	exception.AdditionalData[LastTimestampKey] = exception.AdditionalData[FirstTimestampKey]
+	tracer.exceptionGroups[fingerprint] = exception
+	tracer.exceptions = append(tracer.exceptions, exception)
+}
+
+func (tracer *epsagonTracer) addRunnerDroppedExceptions(event *protocol.Event) {
+	if tracer.droppedExceptions > 0 {
+		event.Resource.Metadata[DroppedExceptionsKey] = strconv.Itoa(tracer.droppedExceptions)
+	}
+}
@@ -0,0 +1,155 @@
+package tracer_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/epsagon/epsagon-go/protocol"
+	"github.com/epsagon/epsagon-go/tracer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type customError struct {
+	code int
+}
+
+func (err *customError) Error() string {
+	return fmt.Sprintf("custom error %d", err.code)
+}
+
+// stackFrame and stackTrace mimic the github.com/pkg/errors types
+type stackFrame uintptr
+type stackTrace []stackFrame
+
+type stackError struct {
+	message string
+	stack   []uintptr
+}
+
+func newStackError(message string) *stackError {
+	pcs := make([]uintptr, 32)
+	return &stackError{message: message, stack: pcs[:runtime.Callers(1, pcs)]}
+}
+
+func (err *stackError) Error() string {
+	return err.message
+}
+
+func (err *stackError) StackTrace() stackTrace {
+	frames := make(stackTrace, len(err.stack))
+	for i, pc := range err.stack {
+		frames[i] = stackFrame(pc)
+	}
+	return frames
+}
+
+func getExceptionFrames(exception *protocol.Exception) []tracer.StackFrame {
+	var frames []tracer.StackFrame
+	Expect(json.Unmarshal([]byte(exception.AdditionalData[tracer.FramesKey]), &frames)).To(Succeed())
+	return frames
+}
+
+func getExceptionErrorChain(exception *protocol.Exception) []tracer.ChainedError {
+	var chain []tracer.ChainedError
+	Expect(json.Unmarshal([]byte(exception.AdditionalData[tracer.ErrorChainKey]), &chain)).To(Succeed())
+	return chain
+}
+
+var _ = Describe("NewException", func() {
+	It("Parses the caller stack into frames", func() {
+		exception := tracer.NewException("type", "message", nil)
+		Expect(exception.Type).To(Equal("type"))
+		Expect(exception.Message).To(Equal("message"))
+		Expect(exception.Traceback).NotTo(BeEmpty())
+		frames := getExceptionFrames(exception)
+		Expect(frames).NotTo(BeEmpty())
+		Expect(frames[0].Function).To(ContainSubstring("tracer_test"))
+		Expect(frames[0].File).To(HaveSuffix("exceptions_test.go"))
+		Expect(frames[0].Line).To(BeNumerically(">", 0))
+		Expect(exception.Traceback).To(HavePrefix(
+			fmt.Sprintf("%s\n\t%s:%d\n", frames[0].Function, frames[0].File, frames[0].Line)))
+		Expect(exception.AdditionalData).NotTo(HaveKey(tracer.ErrorChainKey))
+	})
+	It("Takes the time from the clock of the given tracer", func() {
+		now := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+		currentTracer := &tracer.MockedEpsagonTracer{
+			Config: &tracer.Config{Clock: func() time.Time { return now }},
+		}
+		exception := tracer.NewTracerException(currentTracer, "type", "message", nil)
+		Expect(exception.Time).To(Equal(tracer.Timestamp(now)))
+		frames := getExceptionFrames(exception)
+		Expect(frames[0].Function).To(ContainSubstring("tracer_test"))
+		Expect(frames[0].File).To(HaveSuffix("exceptions_test.go"))
+	})
+	It("Adds the wrapped error chain", func() {
+		err := fmt.Errorf("outer: %w", &customError{code: 3})
+		exception := tracer.NewException("type", err.Error(), err)
+		chain := getExceptionErrorChain(exception)
+		Expect(chain).To(HaveLen(2))
+		Expect(chain[0].Type).To(Equal("*fmt.wrapError"))
+		Expect(chain[0].Message).To(Equal("outer: custom error 3"))
+		Expect(chain[1].Type).To(Equal("*tracer_test.customError"))
+		Expect(chain[1].Message).To(Equal("custom error 3"))
+	})
+	It("Uses the stack trace carried by the error", func() {
+		inner := newStackError("inner")
+		err := fmt.Errorf("outer: %w", inner)
+		exception := tracer.NewException("type", err.Error(), err)
+		frames := getExceptionFrames(exception)
+		Expect(frames[0].Function).To(HaveSuffix("newStackError"))
+		Expect(exception.Traceback).To(HavePrefix(frames[0].Function))
+	})
+	It("Marks standard library and dependency frames as not in app", func() {
+		exception := tracer.NewException("type", "message", errors.New("plain"))
+		checked := 0
+		for _, frame := range getExceptionFrames(exception) {
+			if frame.Function == "testing.tRunner" || frame.Function == "runtime.goexit" ||
+				frame.Function == "github.com/onsi/ginkgo.RunSpecs" {
+				Expect(frame.InApp).To(BeFalse())
+				checked++
+			}
+		}
+		Expect(checked).To(BeNumerically(">", 0))
+	})
+})
+
+var _ = Describe("exception grouping", func() {
+	It("Groups identical exceptions with a count", func() {
+		trace := testWithTracer(nil, func() {
+			for i := 0; i < 100; i++ {
+				tracer.GlobalTracer.AddExceptionTypeAndMessage("aws-sdk-go", fmt.Sprintf("failed request %d", i))
+			}
+		})
+		Expect(trace).NotTo(BeNil())
+		Expect(trace.Exceptions).To(HaveLen(1))
+		exception := trace.Exceptions[0]
+		Expect(exception.AdditionalData[tracer.ExceptionCountKey]).To(Equal("100"))
+		Expect(exception.AdditionalData[tracer.FingerprintKey]).NotTo(BeEmpty())
+		Expect(exception.AdditionalData[tracer.FirstTimestampKey]).NotTo(BeEmpty())
+		Expect(exception.AdditionalData[tracer.LastTimestampKey]).NotTo(BeEmpty())
+	})
+	It("Caps the number of distinct exceptions", func() {
+		trace := testWithTracer(nil, func() {
+			for i := 0; i < 2*tracer.DefaultMaxExceptions; i++ {
+				tracer.GlobalTracer.AddExceptionTypeAndMessage(fmt.Sprintf("type %d", i), "message")
+			}
+		})
+		Expect(trace).NotTo(BeNil())
+		Expect(trace.Exceptions).To(HaveLen(tracer.DefaultMaxExceptions))
+		Expect(trace.Exceptions[0].Type).To(Equal("type 0"))
+	})
+})
+
+var _ = Describe("ExceptionFingerprint", func() {
+	It("Ignores the variable parts of the message", func() {
+		first := &protocol.Exception{Type: "type", Message: "user 1234 not found (0xc000123)"}
+		second := &protocol.Exception{Type: "type", Message: "user 98 not found (0xc000456)"}
+		other := &protocol.Exception{Type: "other", Message: "user 1234 not found (0xc000123)"}
+		Expect(tracer.ExceptionFingerprint(first)).To(Equal(tracer.ExceptionFingerprint(second)))
+		Expect(tracer.ExceptionFingerprint(first)).NotTo(Equal(tracer.ExceptionFingerprint(other)))
+	})
+})
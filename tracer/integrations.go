@@ -0,0 +1,56 @@
+package tracer
+
+import (
+	"os"
+	"strings"
+)
+
+// DisabledIntegrationsEnvVar is the environment variable that sets Config.DisabledIntegrations,
+// a comma separated list of integration names
+const DisabledIntegrationsEnvVar = "EPSAGON_DISABLED_INTEGRATIONS"
+
+// Integration names, to use in Config.DisabledIntegrations
+const (
+	AWSSDKIntegration     = "aws-sdk-go"
+	AWSSDKV2Integration   = "aws-sdk-go-v2"
+	HTTPClientIntegration = "http-client"
+	HTTPServerIntegration = "http-server"
+	GinIntegration        = "gin"
+	FiberIntegration      = "fiber"
+	MongoIntegration      = "mongo"
+	RedisIntegration      = "redis"
+)
+
+// IntegrationDisabled returns true if the given integration is disabled, by
+// Config.DisabledIntegrations, by the named configuration of the config, or by
+// the EPSAGON_DISABLED_INTEGRATIONS environment variable when neither is set
+func (config *Config) IntegrationDisabled(integration string) bool {
+	var disabled []string
+	if config != nil {
+		disabled = config.DisabledIntegrations
+		if len(disabled) == 0 && len(config.ConfigName) > 0 {
+			if registered := RegisteredConfig(config.ConfigName); registered != nil {
+				disabled = registered.DisabledIntegrations
+			}
+		}
+	}
+	if len(disabled) == 0 {
+		disabled = parseIntegrations(os.Getenv(DisabledIntegrationsEnvVar))
+	}
+	for _, name := range disabled {
+		if strings.EqualFold(name, integration) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseIntegrations(rawIntegrations string) []string {
+	var integrations []string
+	for _, name := range strings.Split(rawIntegrations, ",") {
+		if name = strings.TrimSpace(name); len(name) > 0 {
+			integrations = append(integrations, name)
+		}
+	}
+	return integrations
+}
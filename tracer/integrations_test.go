@@ -0,0 +1,35 @@
+package tracer_test
+
+import (
+	"os"
+
+	"github.com/epsagon/epsagon-go/tracer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("IntegrationDisabled", func() {
+	AfterEach(func() {
+		os.Unsetenv(tracer.DisabledIntegrationsEnvVar)
+		tracer.UnregisterConfig("named")
+	})
+
+	It("Checks the configured integrations", func() {
+		config := &tracer.Config{DisabledIntegrations: []string{"Redis"}}
+		Expect(config.IntegrationDisabled(tracer.RedisIntegration)).To(BeTrue())
+		Expect(config.IntegrationDisabled(tracer.MongoIntegration)).To(BeFalse())
+		var nilConfig *tracer.Config
+		Expect(nilConfig.IntegrationDisabled(tracer.RedisIntegration)).To(BeFalse())
+	})
+
+	It("Falls back to the named configuration and the environment variable", func() {
+		os.Setenv(tracer.DisabledIntegrationsEnvVar, " mongo, gin ")
+		Expect((&tracer.Config{}).IntegrationDisabled(tracer.GinIntegration)).To(BeTrue())
+		Expect((&tracer.Config{}).IntegrationDisabled(tracer.FiberIntegration)).To(BeFalse())
+
+		tracer.RegisterConfig("named", &tracer.Config{DisabledIntegrations: []string{tracer.FiberIntegration}})
+		named := &tracer.Config{ConfigName: "named"}
+		Expect(named.IntegrationDisabled(tracer.FiberIntegration)).To(BeTrue())
+		Expect(named.IntegrationDisabled(tracer.GinIntegration)).To(BeFalse())
+	})
+})
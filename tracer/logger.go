@@ -0,0 +1,101 @@
+package tracer
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// Logger is the logger of Epsagon's internal diagnostics, set it as Config.Logger
+// or with SetDefaultLogger to send them to the application logs
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// NopLogger is a Logger that discards all messages
+var NopLogger Logger = nopLogger{}
+
+type nopLogger struct{}
+
+func (nopLogger) Debugf(string, ...interface{}) {}
+func (nopLogger) Infof(string, ...interface{})  {}
+func (nopLogger) Warnf(string, ...interface{})  {}
+func (nopLogger) Errorf(string, ...interface{}) {}
+
+type stdLogger struct {
+	logger *log.Logger
+}
+
+// NewStdLogger creates a Logger that writes all messages to the given
+// standard library logger, or to the log package standard logger if nil
+func NewStdLogger(logger *log.Logger) Logger {
+	return stdLogger{logger: logger}
+}
+
+func (l stdLogger) output(level, format string, args ...interface{}) {
+	message := fmt.Sprintf("EPSAGON %s: %s", level, strings.TrimSuffix(fmt.Sprintf(format, args...), "\n"))
+	if l.logger == nil {
+		log.Output(3, message)
+		return
+	}
+	l.logger.Output(3, message)
+}
+
+func (l stdLogger) Debugf(format string, args ...interface{}) { l.output("DEBUG", format, args...) }
+func (l stdLogger) Infof(format string, args ...interface{})  { l.output("INFO", format, args...) }
+func (l stdLogger) Warnf(format string, args ...interface{})  { l.output("WARN", format, args...) }
+func (l stdLogger) Errorf(format string, args ...interface{}) { l.output("ERROR", format, args...) }
+
+// warningsLogger is the default logger outside of debug mode, it writes
+// warnings and errors to the log package standard logger and discards the rest
+type warningsLogger struct{}
+
+func (warningsLogger) Debugf(string, ...interface{}) {}
+func (warningsLogger) Infof(string, ...interface{})  {}
+func (warningsLogger) Warnf(format string, args ...interface{}) {
+	stdLogger{}.output("WARN", format, args...)
+}
+func (warningsLogger) Errorf(format string, args ...interface{}) {
+	stdLogger{}.output("ERROR", format, args...)
+}
+
+type loggerHolder struct {
+	logger Logger
+}
+
+var defaultLogger atomic.Value
+
+// SetDefaultLogger sets the logger used when Config.Logger is not set, nil
+// restores the default: the log package standard logger in debug mode and
+// only warnings and errors otherwise
+func SetDefaultLogger(logger Logger) {
+	defaultLogger.Store(loggerHolder{logger: logger})
+}
+
+// DefaultLogger returns the logger set by SetDefaultLogger, or the default
+// logger of the given debug mode. EPSAGON_DEBUG enables the debug mode too
+func DefaultLogger(debug bool) Logger {
+	if holder, _ := defaultLogger.Load().(loggerHolder); holder.logger != nil {
+		return holder.logger
+	}
+	if debug || strings.ToUpper(os.Getenv("EPSAGON_DEBUG")) == "TRUE" {
+		return NewStdLogger(nil)
+	}
+	return warningsLogger{}
+}
+
+// GetLogger returns the logger of the config, Config.Logger if set or the default logger otherwise
+func (config *Config) GetLogger() Logger {
+	if config == nil {
+		return DefaultLogger(false)
+	}
+	if config.Logger != nil {
+		return config.Logger
+	}
+	return DefaultLogger(config.Debug)
+}
@@ -0,0 +1,105 @@
+package tracer_test
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/epsagon/epsagon-go/epsagontest"
+	"github.com/epsagon/epsagon-go/tracer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type recordingLogger struct {
+	lock     sync.Mutex
+	messages []string
+}
+
+func (l *recordingLogger) record(level, format string, args ...interface{}) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.messages = append(l.messages, level+" "+fmt.Sprintf(format, args...))
+}
+
+func (l *recordingLogger) Messages() []string {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return append([]string{}, l.messages...)
+}
+
+func (l *recordingLogger) Debugf(format string, args ...interface{}) {
+	l.record("debug", format, args...)
+}
+func (l *recordingLogger) Infof(format string, args ...interface{}) {
+	l.record("info", format, args...)
+}
+func (l *recordingLogger) Warnf(format string, args ...interface{}) {
+	l.record("warn", format, args...)
+}
+func (l *recordingLogger) Errorf(format string, args ...interface{}) {
+	l.record("error", format, args...)
+}
+
+var _ = Describe("Logger", func() {
+	AfterEach(func() {
+		tracer.SetDefaultLogger(nil)
+		os.Unsetenv("EPSAGON_DEBUG")
+	})
+
+	It("Only writes warnings and errors by default", func() {
+		var buf bytes.Buffer
+		log.SetOutput(&buf)
+		defer log.SetOutput(os.Stderr)
+		logger := (&tracer.Config{}).GetLogger()
+		logger.Debugf("debug message")
+		logger.Infof("info message")
+		logger.Warnf("warn message")
+		logger.Errorf("error message")
+		Expect(buf.String()).NotTo(ContainSubstring("debug message"))
+		Expect(buf.String()).NotTo(ContainSubstring("info message"))
+		Expect(buf.String()).To(ContainSubstring("EPSAGON WARN: warn message"))
+		Expect(buf.String()).To(ContainSubstring("EPSAGON ERROR: error message"))
+	})
+
+	It("Writes every message in debug mode", func() {
+		var buf bytes.Buffer
+		log.SetOutput(&buf)
+		defer log.SetOutput(os.Stderr)
+		(&tracer.Config{Debug: true}).GetLogger().Debugf("debug message")
+		os.Setenv("EPSAGON_DEBUG", "true")
+		tracer.DefaultLogger(false).Infof("info message")
+		Expect(buf.String()).To(ContainSubstring("EPSAGON DEBUG: debug message"))
+		Expect(buf.String()).To(ContainSubstring("EPSAGON INFO: info message"))
+	})
+
+	It("Prefers the config logger to the default logger", func() {
+		configLogger, defaultLogger := &recordingLogger{}, &recordingLogger{}
+		tracer.SetDefaultLogger(defaultLogger)
+		Expect((&tracer.Config{Logger: configLogger}).GetLogger()).To(BeIdenticalTo(configLogger))
+		Expect((&tracer.Config{Debug: true}).GetLogger()).To(BeIdenticalTo(defaultLogger))
+	})
+
+	It("Writes leveled messages to a standard library logger", func() {
+		var buf bytes.Buffer
+		logger := tracer.NewStdLogger(log.New(&buf, "", 0))
+		logger.Warnf("dropping %d events\n", 3)
+		Expect(buf.String()).To(Equal("EPSAGON WARN: dropping 3 events\n"))
+	})
+
+	It("Logs send failures with the config logger", func() {
+		collector := epsagontest.NewFakeCollector("token")
+		defer collector.Close()
+		collector.RespondWith(http.StatusInternalServerError)
+		logger := &recordingLogger{}
+		config := collector.TracerConfig("app")
+		config.Logger = logger
+		testTracer := tracer.CreateTracer(config)
+		testTracer.Start()
+		testTracer.Stop()
+		Expect(logger.Messages()).To(ContainElement(ContainSubstring("error Error while sending traces")))
+	})
+})
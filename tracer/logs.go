@@ -0,0 +1,90 @@
+package tracer
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/epsagon/epsagon-go/protocol"
+)
+
+// LogsKey is the key for captured log lines in resource metadata
+const LogsKey = "logs"
+
+// TraceIDKey is the key for the trace identifier in resource metadata
+const TraceIDKey = "trace_id"
+
+// DefaultMaxCapturedLogs is the default number of last log lines attached to the runner event
+const DefaultMaxCapturedLogs = 50
+
+// MaxLogMessageSize is the maximum size of a captured log message (in bytes)
+const MaxLogMessageSize = 1024
+
+// LogRecord is a log line captured by one of the logging adapters
+type LogRecord struct {
+	Time    float64 `json:"timestamp"`
+	Level   string  `json:"level"`
+	Message string  `json:"message"`
+}
+
+// NewLogRecord creates a LogRecord from a log line logged at the given time
+func NewLogRecord(logTime time.Time, level, message string) LogRecord {
+	return LogRecord{
+		Time:    float64(logTime.UnixNano()) / float64(time.Second),
+		Level:   level,
+		Message: message,
+	}
+}
+
+// LogsTracer is implemented by tracers that capture log lines,
+// it is not part of Tracer so that other Tracer implementations are not required to
+type LogsTracer interface {
+	// AddLog Adds a captured log line that will be sent on the runner event
+	AddLog(LogRecord)
+}
+
+// AddTracerLog adds a captured log line to the given tracer,
+// ignoring it if the tracer does not implement LogsTracer
+func AddTracerLog(currentTracer Tracer, record LogRecord) {
+	if logsTracer, ok := currentTracer.(LogsTracer); ok {
+		logsTracer.AddLog(record)
+	}
+}
+
+func (tracer *epsagonTracer) captureLog(record LogRecord) {
+	if len(record.Message) > MaxLogMessageSize {
+		record.Message = record.Message[:MaxLogMessageSize]
+	}
+	tracer.logs = append(tracer.logs, record)
+	if len(tracer.logs) > tracer.Config.MaxCapturedLogs {
+		tracer.logs = tracer.logs[len(tracer.logs)-tracer.Config.MaxCapturedLogs:]
+	}
+}
+
+func (tracer *epsagonTracer) addRunnerLogs(event *protocol.Event) {
+	event.Resource.Metadata[TraceIDKey] = tracer.traceID
+	if len(tracer.logs) == 0 {
+		return
+	}
+	jsonString, err := json.Marshal(tracer.logs)
+	if err != nil {
+		if tracer.Config.Debug {
+			tracer.Config.GetLogger().Debugf("failed appending logs")
+		}
+	} else {
+		event.Resource.Metadata[LogsKey] = string(jsonString)
+	}
+}
+
+// AddLog adds a captured log line to the tracer, lines logged after
+// the tracer has stopped are dropped
+func (tracer *epsagonTracer) AddLog(record LogRecord) {
+	select {
+	case tracer.logsPipe <- record:
+	case <-tracer.stopped:
+	}
+}
+
+// GetTraceID returns the identifier of the trace collected by the tracer
+func (tracer *epsagonTracer) GetTraceID() string {
+	return tracer.traceID
+}
@@ -7,7 +7,8 @@ import (
 	"github.com/epsagon/epsagon-go/protocol"
 )
 
-const maskedValue = "****"
+// MaskedValue replaces the values of ignored keys in events metadata
+const MaskedValue = "****"
 
 func arrayToHitMap(arr []string) map[string]bool {
 	hitMap := make(map[string]bool)
@@ -36,7 +37,7 @@ func maskNestedJSONKeys(decodedJSON interface{}, ignoredKeysMap map[string]bool)
 	case reflect.Map:
 		for _, key := range decodedValue.MapKeys() {
 			if ignoredKeysMap[key.String()] {
-				decodedValue.SetMapIndex(key, reflect.ValueOf(maskedValue))
+				decodedValue.SetMapIndex(key, reflect.ValueOf(MaskedValue))
 				changed = true
 			} else {
 				nestedValue := decodedValue.MapIndex(key)
@@ -51,33 +52,40 @@ func maskNestedJSONKeys(decodedJSON interface{}, ignoredKeysMap map[string]bool)
 	return decodedValue.Interface(), changed
 }
 
-// maskIgnoredKeys masks all the keys in the
+// MaskIgnoredKeys masks all the keys in the
 // event resource metadata that are in ignoredKeys, swapping them with '****'.
 // Metadata values that are json decodable will have their nested keys masked as well.
-func (tracer *epsagonTracer) maskEventIgnoredKeys(event *protocol.Event, ignoredKeys []string) {
+// An error is returned if a masked json value could not be encoded back
+func MaskIgnoredKeys(event *protocol.Event, ignoredKeys []string) (err error) {
 	ignoredKeysMap := arrayToHitMap(ignoredKeys)
 	for key, value := range event.Resource.Metadata {
 		if ignoredKeysMap[key] {
-			event.Resource.Metadata[key] = maskedValue
+			event.Resource.Metadata[key] = MaskedValue
 		} else {
 			var decodedJSON interface{}
-			err := json.Unmarshal([]byte(value), &decodedJSON)
-			if err == nil {
+			if json.Unmarshal([]byte(value), &decodedJSON) == nil {
 				newValue, changed := maskNestedJSONKeys(decodedJSON, ignoredKeysMap)
 				if changed {
-					encodedNewValue, err := json.Marshal(newValue)
-					if err == nil {
+					encodedNewValue, encodeErr := json.Marshal(newValue)
+					if encodeErr == nil {
 						event.Resource.Metadata[key] = string(encodedNewValue)
 					} else {
-						exception := createException("internal json encode error", err.Error())
-						if tracer.Stopped() {
-							tracer.exceptions = append(tracer.exceptions, exception)
-						} else {
-							tracer.AddException(exception)
-						}
+						err = encodeErr
 					}
 				}
 			}
 		}
 	}
+	return
+}
+
+func (tracer *epsagonTracer) maskEventIgnoredKeys(event *protocol.Event, ignoredKeys []string) {
+	if err := MaskIgnoredKeys(event, ignoredKeys); err != nil {
+		exception := NewTracerException(tracer, "internal json encode error", err.Error(), err)
+		if tracer.Stopped() {
+			tracer.recordException(exception)
+		} else {
+			tracer.AddException(exception)
+		}
+	}
 }
@@ -28,7 +28,7 @@ var _ = Describe("mask_ignored_keys", func() {
 				testTracer.Config.IgnoredKeys = ignoredKeys
 				testTracer.maskEventIgnoredKeys(event, ignoredKeys)
 				Expect(event.Resource.Metadata["not-ignored"]).To(Equal("hello"))
-				Expect(event.Resource.Metadata["ignored"]).To(Equal(maskedValue))
+				Expect(event.Resource.Metadata["ignored"]).To(Equal(MaskedValue))
 			})
 		})
 		Context("test matrix", func() {
@@ -51,7 +51,7 @@ var _ = Describe("mask_ignored_keys", func() {
 				"passes matrix sanity": {
 					metadata:         map[string]string{"to-be-ignored": "bye", "not-ignored": "hello"},
 					ignoredKeys:      []string{"to-be-ignored"},
-					expectedMetadata: map[string]string{"to-be-ignored": maskedValue, "not-ignored": "hello"},
+					expectedMetadata: map[string]string{"to-be-ignored": MaskedValue, "not-ignored": "hello"},
 				},
 				"handles json map without ignored keys": {
 					metadata:         map[string]string{"not-ignored": "hello", "other-not-ignored": "{\"hello\":\"world\"}"},
@@ -61,7 +61,7 @@ var _ = Describe("mask_ignored_keys", func() {
 				"handles json map *with* ignored keys": {
 					metadata:         map[string]string{"not-ignored": "hello", "other-not-ignored": "{\"to-be-ignored\":\"world\"}"},
 					ignoredKeys:      []string{"to-be-ignored"},
-					expectedMetadata: map[string]string{"not-ignored": "hello", "other-not-ignored": fmt.Sprintf("{\"to-be-ignored\":\"%s\"}", maskedValue)},
+					expectedMetadata: map[string]string{"not-ignored": "hello", "other-not-ignored": fmt.Sprintf("{\"to-be-ignored\":\"%s\"}", MaskedValue)},
 				},
 				"handles json nested array and map without ignored keys": {
 					metadata:         map[string]string{"not-ignored": "hello", "other-not-ignored": "[{\"hello\":\"world\"},{\"erez\":\"is-cool\"}]"},
@@ -78,7 +78,7 @@ var _ = Describe("mask_ignored_keys", func() {
 						"not-ignored": "hello",
 						"other-not-ignored": fmt.Sprintf(
 							"[{\"to-be-ignored\":\"%s\"},{\"erez\":\"is-cool\"}]",
-							maskedValue),
+							MaskedValue),
 					},
 				},
 				"handles json nested map *with* ignored keys": {
@@ -91,7 +91,7 @@ var _ = Describe("mask_ignored_keys", func() {
 						"not-ignored": "hello",
 						"other-not-ignored": fmt.Sprintf(
 							"{\"wait\":{\"for\":{\"it\":{\"to-be-ignored\":\"%s\"},\"not\":[\"I\",\"are\",\"baboon\"]},\"not\":\"it\"}}",
-							maskedValue,
+							MaskedValue,
 						),
 					},
 				},
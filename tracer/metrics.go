@@ -0,0 +1,155 @@
+package tracer
+
+import (
+	"encoding/json"
+	"math"
+
+	"github.com/epsagon/epsagon-go/protocol"
+)
+
+// MetricsKey is the key for custom metrics in resource metadata
+const MetricsKey = "metrics"
+
+// MaxMetricsCount is the maximum number of distinct metrics kept per trace
+const MaxMetricsCount = 100
+
+// MetricKind is the way a custom metric is aggregated within a trace
+type MetricKind int
+
+const (
+	// CounterMetric values are summed
+	CounterMetric MetricKind = iota
+	// GaugeMetric keeps the last reported value
+	GaugeMetric
+	// HistogramMetric keeps the count, sum, min and max of the observations
+	HistogramMetric
+)
+
+// String returns the name of the metric kind as sent in the trace
+func (kind MetricKind) String() string {
+	switch kind {
+	case CounterMetric:
+		return "counter"
+	case GaugeMetric:
+		return "gauge"
+	case HistogramMetric:
+		return "histogram"
+	}
+	return "unknown"
+}
+
+type epsagonMetric struct {
+	kind  MetricKind
+	name  string
+	value float64
+	unit  string
+}
+
+// aggregatedMetric is the representation of a metric in the runner event.
+// Value is the sum for counters and histograms, and the last value for gauges
+type aggregatedMetric struct {
+	Kind  string  `json:"type"`
+	Unit  string  `json:"unit,omitempty"`
+	Count int     `json:"count"`
+	Value float64 `json:"value"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+}
+
+func newAggregatedMetric(metric epsagonMetric) *aggregatedMetric {
+	return &aggregatedMetric{
+		Kind:  metric.kind.String(),
+		Unit:  metric.unit,
+		Count: 1,
+		Value: metric.value,
+		Min:   metric.value,
+		Max:   metric.value,
+	}
+}
+
+func (aggregated *aggregatedMetric) observe(metric epsagonMetric) {
+	aggregated.Count++
+	aggregated.Min = math.Min(aggregated.Min, metric.value)
+	aggregated.Max = math.Max(aggregated.Max, metric.value)
+	if metric.kind == GaugeMetric {
+		aggregated.Value = metric.value
+	} else {
+		aggregated.Value += metric.value
+	}
+}
+
+func (tracer *epsagonTracer) aggregateMetric(metric epsagonMetric) {
+	if math.IsNaN(metric.value) || math.IsInf(metric.value, 0) {
+		if tracer.Config.Debug {
+			tracer.Config.GetLogger().Debugf("dropping metric %s with invalid value %v", metric.name, metric.value)
+		}
+		return
+	}
+	aggregated, ok := tracer.metrics[metric.name]
+	if !ok {
+		if len(tracer.metrics) >= MaxMetricsCount {
+			if tracer.Config.Debug {
+				tracer.Config.GetLogger().Debugf("dropping metric %s, max metrics count (%d) reached", metric.name, MaxMetricsCount)
+			}
+			return
+		}
+		tracer.metrics[metric.name] = newAggregatedMetric(metric)
+		return
+	}
+	if aggregated.Kind != metric.kind.String() {
+		if tracer.Config.Debug {
+			tracer.Config.GetLogger().Debugf("metric %s was already reported as a %s", metric.name, aggregated.Kind)
+		}
+		return
+	}
+	aggregated.observe(metric)
+}
+
+func (tracer *epsagonTracer) addRunnerMetrics(event *protocol.Event) {
+	if len(tracer.metrics) == 0 {
+		return
+	}
+	jsonString, err := json.Marshal(tracer.metrics)
+	if err != nil {
+		if tracer.Config.Debug {
+			tracer.Config.GetLogger().Debugf("failed appending metrics")
+		}
+	} else {
+		event.Resource.Metadata[MetricsKey] = string(jsonString)
+	}
+}
+
+// AddMetric adds a custom metric observation to the tracer
+func (tracer *epsagonTracer) AddMetric(kind MetricKind, name string, value float64, unit string) {
+	if tracer.Config.Debug {
+		tracer.Config.GetLogger().Debugf("Adding metric: %s %s %v %s", kind, name, value, unit)
+	}
+	select {
+	case tracer.metricsPipe <- epsagonMetric{kind, name, value, unit}:
+	case <-tracer.stopped:
+	}
+}
+
+// MetricsTracer is implemented by tracers that aggregate custom metrics,
+// it is not part of Tracer so that other Tracer implementations are not required to
+type MetricsTracer interface {
+	// AddMetric Adds a custom metric observation that is aggregated within the trace
+	AddMetric(MetricKind, string, float64, string)
+}
+
+// AddTracerMetric adds a custom metric observation to the given tracer,
+// ignoring it if the tracer does not implement MetricsTracer
+func AddTracerMetric(currentTracer Tracer, kind MetricKind, name string, value float64, unit string) {
+	if metricsTracer, ok := currentTracer.(MetricsTracer); ok {
+		metricsTracer.AddMetric(kind, name, value, unit)
+	}
+}
+
+// AddMetric adds a custom metric observation to the global tracer
+func AddMetric(kind MetricKind, name string, value float64, unit string) {
+	if GlobalTracer == nil || GlobalTracer.Stopped() {
+		DefaultLogger(false).Warnf("The tracer is not initialized!")
+		return
+	}
+	AddTracerMetric(GlobalTracer, kind, name, value, unit)
+}
@@ -11,6 +11,9 @@ type MockedEpsagonTracer struct {
 	Exceptions      *[]*protocol.Exception
 	Events          *[]*protocol.Event
 	Labels          map[string]interface{}
+	Metrics         map[string]float64
+	Logs            []LogRecord
+	TraceID         string
 	RunnerException *protocol.Exception
 	Config          *Config
 
@@ -100,6 +103,24 @@ func (t *MockedEpsagonTracer) AddLabel(key string, value interface{}) {
 	t.Labels[key] = value
 }
 
+// AddMetric implements AddMetric, keeping the last value of every metric
+func (t *MockedEpsagonTracer) AddMetric(kind MetricKind, name string, value float64, unit string) {
+	if t.Metrics == nil {
+		t.Metrics = make(map[string]float64)
+	}
+	t.Metrics[name] = value
+}
+
+// AddLog implements AddLog
+func (t *MockedEpsagonTracer) AddLog(record LogRecord) {
+	t.Logs = append(t.Logs, record)
+}
+
+// GetTraceID implements GetTraceID
+func (t *MockedEpsagonTracer) GetTraceID() string {
+	return t.TraceID
+}
+
 // verifyLabel implements verifyLabel
 func (t *MockedEpsagonTracer) verifyLabel(label epsagonLabel) bool {
 	return true
@@ -0,0 +1,99 @@
+package tracer
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+)
+
+const configNameField = "ConfigName"
+
+var (
+	registryLock sync.RWMutex
+	namedConfigs = make(map[string]*Config)
+)
+
+// RegisterConfig registers a named tracer configuration. Tracers created with
+// a Config whose ConfigName is name take the registered values as defaults for
+// every option they leave unset, so a boolean option enabled in the registered
+// configuration cannot be disabled by the tracer's Config. The registered values
+// are not written to the tracer's Config. Registering an existing name replaces it
+func RegisterConfig(name string, config *Config) {
+	if config == nil {
+		config = &Config{}
+	}
+	registered := copyConfig(config)
+	registered.ConfigName = name
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	namedConfigs[name] = registered
+}
+
+// UnregisterConfig removes a named tracer configuration
+func UnregisterConfig(name string) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	delete(namedConfigs, name)
+}
+
+// RegisteredConfig returns a copy of a named tracer configuration,
+// or nil if no configuration is registered with the given name
+func RegisteredConfig(name string) *Config {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	registered, ok := namedConfigs[name]
+	if !ok {
+		return nil
+	}
+	return copyConfig(registered)
+}
+
+// RegisteredConfigNames returns the sorted names of the registered tracer configurations
+func RegisteredConfigNames() []string {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	names := make([]string, 0, len(namedConfigs))
+	for name := range namedConfigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func copyConfig(config *Config) *Config {
+	copied := *config
+	if config.IgnoredKeys != nil {
+		copied.IgnoredKeys = append([]string{}, config.IgnoredKeys...)
+	}
+	if config.DisabledIntegrations != nil {
+		copied.DisabledIntegrations = append([]string{}, config.DisabledIntegrations...)
+	}
+	return &copied
+}
+
+// applyNamedConfig returns a copy of config whose unset options are filled
+// from its named configuration, or config itself if it has no named configuration.
+// Boolean options are enabled if they are enabled in either configuration
+func applyNamedConfig(config *Config) *Config {
+	if len(config.ConfigName) == 0 {
+		return config
+	}
+	registered := RegisteredConfig(config.ConfigName)
+	if registered == nil {
+		config.GetLogger().Warnf("no configuration is registered with the name %s", config.ConfigName)
+		return config
+	}
+	merged := copyConfig(config)
+	mergedValue := reflect.ValueOf(merged).Elem()
+	registeredValue := reflect.ValueOf(registered).Elem()
+	for i := 0; i < mergedValue.NumField(); i++ {
+		field := mergedValue.Field(i)
+		if mergedValue.Type().Field(i).Name == configNameField || !field.CanSet() {
+			continue
+		}
+		if reflect.DeepEqual(field.Interface(), reflect.Zero(field.Type()).Interface()) {
+			field.Set(registeredValue.Field(i))
+		}
+	}
+	return merged
+}
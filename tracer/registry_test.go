@@ -0,0 +1,102 @@
+package tracer_test
+
+import (
+	"time"
+
+	"github.com/epsagon/epsagon-go/epsagon"
+	"github.com/epsagon/epsagon-go/epsagontest"
+	"github.com/epsagon/epsagon-go/protocol"
+	"github.com/epsagon/epsagon-go/tracer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Named configurations", func() {
+	AfterEach(func() {
+		for _, name := range tracer.RegisteredConfigNames() {
+			tracer.UnregisterConfig(name)
+		}
+	})
+
+	It("Registers copies of the configurations", func() {
+		config := &tracer.Config{ApplicationName: "billing", IgnoredKeys: []string{"card"}}
+		tracer.RegisterConfig("billing", config)
+		tracer.RegisterConfig("shipping", &tracer.Config{ApplicationName: "shipping"})
+		config.IgnoredKeys[0] = "changed"
+
+		registered := tracer.RegisteredConfig("billing")
+		Expect(registered.ApplicationName).To(Equal("billing"))
+		Expect(registered.ConfigName).To(Equal("billing"))
+		Expect(registered.IgnoredKeys).To(Equal([]string{"card"}))
+		Expect(tracer.RegisteredConfigNames()).To(Equal([]string{"billing", "shipping"}))
+		Expect(tracer.RegisteredConfig("unknown")).To(BeNil())
+
+		tracer.UnregisterConfig("shipping")
+		Expect(tracer.RegisteredConfigNames()).To(Equal([]string{"billing"}))
+	})
+
+	It("Does not share the registered configuration with new tracers", func() {
+		tracer.RegisterConfig("billing", &tracer.Config{
+			Disable:              true,
+			IgnoredKeys:          []string{"card"},
+			DisabledIntegrations: []string{tracer.GinIntegration},
+		})
+		config := tracer.CreateTracer(&tracer.Config{ConfigName: "billing"}).GetConfig()
+		Expect(config.Disable).To(BeTrue())
+		Expect(config.DisabledIntegrations).To(Equal([]string{tracer.GinIntegration}))
+		config.IgnoredKeys[0] = "changed"
+		config.DisabledIntegrations[0] = "changed"
+
+		registered := tracer.RegisteredConfig("billing")
+		Expect(registered.IgnoredKeys).To(Equal([]string{"card"}))
+		Expect(registered.DisabledIntegrations).To(Equal([]string{tracer.GinIntegration}))
+	})
+
+	It("Does not write the registered configuration to the tracer config", func() {
+		tracer.RegisterConfig("billing", &tracer.Config{ApplicationName: "billing", Token: "first-token"})
+		config := &tracer.Config{ConfigName: "billing"}
+		Expect(tracer.CreateTracer(config).GetConfig().Token).To(Equal("first-token"))
+		Expect(config.ApplicationName).To(BeEmpty())
+		Expect(config.Token).To(BeEmpty())
+
+		tracer.RegisterConfig("billing", &tracer.Config{ApplicationName: "billing", Token: "second-token"})
+		Expect(tracer.CreateTracer(config).GetConfig().Token).To(Equal("second-token"))
+	})
+
+	It("Enables boolean options enabled in either configuration", func() {
+		tracer.RegisterConfig("billing", &tracer.Config{MetadataOnly: true})
+		config := tracer.CreateTracer(&tracer.Config{ConfigName: "billing", Debug: true}).GetConfig()
+		Expect(config.MetadataOnly).To(BeTrue())
+		Expect(config.Debug).To(BeTrue())
+	})
+
+	It("Uses the registered configuration as defaults for new tracers", func() {
+		billingCollector := epsagontest.NewFakeCollector("billing-token")
+		defer billingCollector.Close()
+		shippingCollector := epsagontest.NewFakeCollector("shipping-token")
+		defer shippingCollector.Close()
+		tracer.RegisterConfig("billing", billingCollector.TracerConfig("billing"))
+		tracer.RegisterConfig("shipping", shippingCollector.TracerConfig("shipping"))
+
+		for _, name := range []string{"billing", "shipping"} {
+			config := epsagon.NamedConfig(name)
+			if name == "shipping" {
+				config.ApplicationName = "shipping-override"
+			}
+			epsagon.GoWrapper(config, func() {}, name+"-function")()
+		}
+
+		billingTrace, err := billingCollector.WaitForTrace(time.Second)
+		Expect(err).To(BeNil())
+		Expect(billingTrace.AppName).To(Equal("billing"))
+		Expect(billingTrace.Token).To(Equal("billing-token"))
+		shippingTrace, err := shippingCollector.WaitForTrace(time.Second)
+		Expect(err).To(BeNil())
+		Expect(shippingTrace.AppName).To(Equal("shipping-override"))
+		Expect(shippingTrace.Token).To(Equal("shipping-token"))
+		Expect(shippingTrace.Events).To(ContainElement(
+			WithTransform(func(event *protocol.Event) string {
+				return event.Resource.Name
+			}, Equal("shipping-function"))))
+	})
+})
@@ -0,0 +1,76 @@
+package tracer
+
+import (
+	"bufio"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/epsagon/epsagon-go/protocol"
+)
+
+// Runtime metrics keys in the runner event resource metadata
+const (
+	GoroutinesKey = "runtime.goroutines"
+	HeapAllocKey  = "runtime.heap_alloc"
+	HeapSysKey    = "runtime.heap_sys"
+	GCCountKey    = "runtime.gc_count"
+	GCPauseKey    = "runtime.gc_pause_ns"
+	MaxRSSKey     = "runtime.max_rss_kb"
+)
+
+// RuntimeMetricsEnvVar enables the runtime metrics when set to TRUE
+const RuntimeMetricsEnvVar = "EPSAGON_RUNTIME_METRICS"
+
+const procStatusPath = "/proc/self/status"
+
+// RuntimeStats is a snapshot of the Go runtime, taken when an invocation starts
+type RuntimeStats struct {
+	numGC        uint32
+	pauseTotalNs uint64
+}
+
+// CaptureRuntimeStats returns a snapshot of the current Go runtime stats
+func CaptureRuntimeStats() *RuntimeStats {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	return &RuntimeStats{
+		numGC:        memStats.NumGC,
+		pauseTotalNs: memStats.PauseTotalNs,
+	}
+}
+
+// AddRuntimeMetrics adds the current goroutine count and heap sizes to the
+// runner event, with the GC count and pause total since the snapshot was taken
+func (start *RuntimeStats) AddRuntimeMetrics(event *protocol.Event) {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	metadata := event.Resource.Metadata
+	metadata[GoroutinesKey] = strconv.Itoa(runtime.NumGoroutine())
+	metadata[HeapAllocKey] = strconv.FormatUint(memStats.HeapAlloc, 10)
+	metadata[HeapSysKey] = strconv.FormatUint(memStats.HeapSys, 10)
+	metadata[GCCountKey] = strconv.FormatUint(uint64(memStats.NumGC-start.numGC), 10)
+	metadata[GCPauseKey] = strconv.FormatUint(memStats.PauseTotalNs-start.pauseTotalNs, 10)
+	if maxRSS, ok := readMaxRSS(); ok {
+		metadata[MaxRSSKey] = maxRSS
+	}
+}
+
+// readMaxRSS returns the peak resident set size (in KB) of the process,
+// which is only available on Linux
+func readMaxRSS() (string, bool) {
+	file, err := os.Open(procStatusPath)
+	if err != nil {
+		return "", false
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "VmHWM:" {
+			return fields[1], true
+		}
+	}
+	return "", false
+}
@@ -7,11 +7,9 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
-	"log"
 	"net/http"
 	"os"
 	"runtime"
-	"runtime/debug"
 	"strconv"
 	"strings"
 	"sync"
@@ -45,11 +43,17 @@ const MaxLabelsSize = 10 * 1024
 // MaxTraceSizeEnvVar max trace size environment variable
 const MaxTraceSizeEnvVar = "EPSAGON_MAX_TRACE_SIZE"
 
+// TraceIDHeaderEnvVar is the environment variable that sets Config.TraceIDHeader
+const TraceIDHeaderEnvVar = "EPSAGON_TRACE_ID_HEADER"
+
 // LabelsKey is the key for labels in resource metadata
 const LabelsKey = "labels"
 
 const IsTrimmedKey = "is_trimmed"
 
+// InterruptedKey marks a runner event that was interrupted by a shutdown
+const InterruptedKey = "interrupted"
+
 const EpsagonHTTPTraceIDKey = "http_trace_id"
 const EpsagonRequestTraceIDKey = "request_trace_id"
 const AwsServiceKey = "aws.service"
@@ -59,12 +63,22 @@ var strongKeys = map[string]bool{
 	EpsagonRequestTraceIDKey: true,
 	AwsServiceKey:            true,
 	LabelsKey:                true,
+	MetricsKey:               true,
+	TraceIDKey:               true,
+	InterruptedKey:           true,
+	GoroutinesKey:            true,
+	HeapAllocKey:             true,
+	HeapSysKey:               true,
+	GCCountKey:               true,
+	GCPauseKey:               true,
+	MaxRSSKey:                true,
 	"aws_account":            true,
 	"region":                 true,
 	"log_group_name":         true,
 	"log_stream_name":        true,
 	"sequence_number":        true,
 	"item_hash":              true,
+	"message_ids":            true,
 }
 
 // threshold in milliseconds to send the trace before a Lambda timeout occurs
@@ -116,6 +130,18 @@ type Config struct {
 	TestMode        bool     // TestMode sending traces
 	IgnoredKeys     []string // IgnoredKeys are keys that will be masked from events metadata
 	MaxTraceSize    int      // MaxTraceSize is the maximum allowed trace size (in bytes)
+	MaxCapturedLogs int      // MaxCapturedLogs is the number of last captured log lines sent on the runner event
+	MaxExceptions   int      // MaxExceptions is the maximum number of distinct exceptions kept per trace
+	RuntimeMetrics  bool     // RuntimeMetrics adds Go runtime metrics to the runner event
+	TraceIDHeader   string   // TraceIDHeader is the response header the server wrappers set to the trace ID, disabled if empty
+	ConfigName      string   // ConfigName is the name of a registered configuration used as defaults, see RegisterConfig
+	// DisabledIntegrations are the names of the integrations that pass calls through without tracing
+	DisabledIntegrations []string
+	// Logger receives the internal diagnostics, see GetLogger for the default
+	Logger Logger
+	// Clock and IDGenerator replace the system clock and random UUIDs, for deterministic traces
+	Clock       Clock
+	IDGenerator IDGenerator
 }
 
 type epsagonLabel struct {
@@ -124,17 +150,24 @@ type epsagonLabel struct {
 }
 
 type epsagonTracer struct {
-	Config *Config
+	Config  *Config
+	traceID string
 
 	eventsPipe          chan *protocol.Event
 	events              []*protocol.Event
 	runnerExceptionPipe chan *protocol.Exception
 	exceptionsPipe      chan *protocol.Exception
 	labelsPipe          chan epsagonLabel
+	metricsPipe         chan epsagonMetric
 	exceptions          []*protocol.Exception
+	exceptionGroups     map[string]*protocol.Exception
+	droppedExceptions   int
 	runnerException     *protocol.Exception
 	labels              map[string]interface{}
 	labelsSize          int
+	metrics             map[string]*aggregatedMetric
+	logsPipe            chan LogRecord
+	logs                []LogRecord
 
 	closeCmd chan struct{}
 	stopped  chan struct{}
@@ -150,7 +183,7 @@ func (tracer *epsagonTracer) Start() {
 	case <-tracer.running:
 		return
 	case <-timer.C:
-		log.Println("Epsagon Tracer couldn't start after one second timeout")
+		tracer.Config.GetLogger().Warnf("Tracer couldn't start after one second timeout")
 	}
 }
 
@@ -165,13 +198,13 @@ func (tracer *epsagonTracer) sendTraces() {
 	tracesReader, err := tracer.getTraceReader()
 	if err != nil {
 		// TODO create an exception and send a trace only with that
-		log.Printf("Epsagon: Encountered an error while marshaling the traces: %v\n", err)
+		tracer.Config.GetLogger().Errorf("Encountered an error while marshaling the traces: %v", err)
 		return
 	}
 	sendTimeout, err := time.ParseDuration(tracer.Config.SendTimeout)
 	if err != nil {
 		if tracer.Config.Debug {
-			log.Printf("Epsagon: Encountered an error while parsing send timeout: %v, using '1s'\n", err)
+			tracer.Config.GetLogger().Debugf("Encountered an error while parsing send timeout: %v, using '1s'", err)
 		}
 		sendTimeout, _ = time.ParseDuration("1s")
 	}
@@ -181,7 +214,7 @@ func (tracer *epsagonTracer) sendTraces() {
 	if !tracer.Config.Disable {
 		if len(tracer.Config.Token) == 0 {
 			if tracer.Config.Debug {
-				log.Printf("Epsagon: empty token, not sending traces\n")
+				tracer.Config.GetLogger().Debugf("empty token, not sending traces")
 			}
 			return
 		}
@@ -189,27 +222,33 @@ func (tracer *epsagonTracer) sendTraces() {
 		if err == nil {
 			req.Header.Set("Content-Type", "application/json")
 			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", tracer.Config.Token))
-			HandleSendTracesResponse(client.Do(req))
+			resp, err := client.Do(req)
+			handleSendTracesResponse(tracer.Config.GetLogger(), resp, err)
 		} else {
 			if tracer.Config.Debug {
-				log.Printf("Epsagon: Encountered an error while trying to send traces: %v\n", err)
+				tracer.Config.GetLogger().Debugf("Encountered an error while trying to send traces: %v", err)
 			}
 		}
 
 	}
 }
 
-// HandleSendTracesResponse handles responses from the trace collector
+// HandleSendTracesResponse handles responses from the trace collector,
+// logging errors with the default logger
 func HandleSendTracesResponse(resp *http.Response, err error) {
+	handleSendTracesResponse(DefaultLogger(false), resp, err)
+}
+
+func handleSendTracesResponse(logger Logger, resp *http.Response, err error) {
 	if err != nil {
-		log.Printf("Error while sending traces \n%v", err)
+		logger.Errorf("Error while sending traces \n%v", err)
 		return
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode >= http.StatusInternalServerError {
 		//safe to ignore the error here
 		respBody, _ := ioutil.ReadAll(resp.Body)
-		log.Printf("Error while sending traces \n%v", string(respBody))
+		logger.Errorf("Error while sending traces \n%v", string(respBody))
 	}
 }
 
@@ -227,7 +266,7 @@ func (tracer *epsagonTracer) addRunnerLabels(event *protocol.Event) {
 	jsonString, err := json.Marshal(tracer.labels)
 	if err != nil {
 		if tracer.Config.Debug {
-			log.Printf("EPSAGON DEBUG failed appending labels")
+			tracer.Config.GetLogger().Debugf("failed appending labels")
 		}
 	} else {
 		event.Resource.Metadata[LabelsKey] = string(jsonString)
@@ -269,7 +308,7 @@ func (tracer *epsagonTracer) stripEvents(traceLength int, marshaler *jsonpb.Mars
 		if traceLength <= tracer.Config.MaxTraceSize {
 			if tracer.Config.Debug {
 				traceLength := traceLength / 1024
-				log.Printf("EPSAGON DEBUG trimmed trace from %dKB to %dKB (max allowed size: %dKB)", originalTraceLength, traceLength, MaxTraceSizeKB)
+				tracer.Config.GetLogger().Debugf("trimmed trace from %dKB to %dKB (max allowed size: %dKB)", originalTraceLength, traceLength, MaxTraceSizeKB)
 			}
 			return true
 		}
@@ -302,6 +341,9 @@ func (tracer *epsagonTracer) getTraceReader() (io.Reader, error) {
 	runnerEvent := tracer.GetRunnerEvent()
 	if runnerEvent != nil {
 		tracer.addRunnerLabels(runnerEvent)
+		tracer.addRunnerMetrics(runnerEvent)
+		tracer.addRunnerLogs(runnerEvent)
+		tracer.addRunnerDroppedExceptions(runnerEvent)
 		tracer.addRunnerException(runnerEvent)
 	}
 	trace := protocol.Trace{
@@ -313,14 +355,14 @@ func (tracer *epsagonTracer) getTraceReader() (io.Reader, error) {
 		Platform:   version,
 	}
 	if tracer.Config.Debug {
-		log.Printf("EPSAGON DEBUG sending trace: %+v\n", trace)
+		tracer.Config.GetLogger().Debugf("sending trace: %+v", trace)
 	}
 	traceJSON, err := tracer.getTraceJSON(&trace, runnerEvent)
 	if err != nil {
 		return nil, err
 	}
 	if tracer.Config.Debug {
-		log.Printf("Final Traces: %s ", traceJSON)
+		tracer.Config.GetLogger().Debugf("Final Traces: %s ", traceJSON)
 	}
 	return bytes.NewBuffer([]byte(traceJSON)), nil
 }
@@ -353,13 +395,13 @@ func fillConfigDefaults(config *Config) {
 	if len(config.Token) == 0 {
 		config.Token = os.Getenv("EPSAGON_TOKEN")
 		if config.Debug {
-			log.Println("EPSAGON DEBUG: setting token from environment variable")
+			config.GetLogger().Debugf("setting token from environment variable")
 		}
 	}
 	if config.MaxTraceSize > MaxTraceSize || config.MaxTraceSize < 0 {
 		config.MaxTraceSize = DefaultMaxTraceSize
 		if config.Debug {
-			log.Printf("EPSAGON DEBUG: MaxTraceSize is invalid (must be <= %dKB), using default size (%dKB)\n", MaxTraceSizeKB, DefaultMaxTraceSizeKB)
+			config.GetLogger().Debugf("MaxTraceSize is invalid (must be <= %dKB), using default size (%dKB)", MaxTraceSizeKB, DefaultMaxTraceSizeKB)
 		}
 	} else {
 		rawTraceSize := os.Getenv(MaxTraceSizeEnvVar)
@@ -369,7 +411,7 @@ func fillConfigDefaults(config *Config) {
 		} else {
 			config.MaxTraceSize = maxTraceSize
 			if config.Debug {
-				log.Printf("EPSAGON DEBUG: setting max trace size (%dKB) from environment variable\n", maxTraceSize/1024)
+				config.GetLogger().Debugf("setting max trace size (%dKB) from environment variable", maxTraceSize/1024)
 			}
 		}
 	}
@@ -391,14 +433,31 @@ func fillConfigDefaults(config *Config) {
 			}
 		}
 		if config.Debug {
-			log.Printf("EPSAGON DEBUG: setting collector url to %s\n", config.CollectorURL)
+			config.GetLogger().Debugf("setting collector url to %s", config.CollectorURL)
 		}
 	}
+	if !config.RuntimeMetrics {
+		if strings.ToUpper(os.Getenv(RuntimeMetricsEnvVar)) == "TRUE" {
+			config.RuntimeMetrics = true
+		}
+	}
+	if len(config.TraceIDHeader) == 0 {
+		config.TraceIDHeader = os.Getenv(TraceIDHeaderEnvVar)
+	}
+	if len(config.DisabledIntegrations) == 0 {
+		config.DisabledIntegrations = parseIntegrations(os.Getenv(DisabledIntegrationsEnvVar))
+	}
+	if config.MaxCapturedLogs <= 0 {
+		config.MaxCapturedLogs = DefaultMaxCapturedLogs
+	}
+	if config.MaxExceptions <= 0 {
+		config.MaxExceptions = DefaultMaxExceptions
+	}
 	sendTimeout := os.Getenv("EPSAGON_SEND_TIMEOUT_SEC")
 	if len(sendTimeout) != 0 {
 		config.SendTimeout = sendTimeout
 		if config.Debug {
-			log.Println("EPSAGON DEBUG: setting send timeout from environment variable")
+			config.GetLogger().Debugf("setting send timeout from environment variable")
 		}
 	}
 }
@@ -411,22 +470,30 @@ func CreateTracer(config *Config) Tracer {
 	if config == nil {
 		config = &Config{}
 	}
+	config = applyNamedConfig(config)
 	fillConfigDefaults(config)
+	config = applyConfigOverrides(config)
 	tracer := &epsagonTracer{
 		Config:              config,
+		traceID:             config.NewID(),
 		eventsPipe:          make(chan *protocol.Event),
 		events:              make([]*protocol.Event, 0, 0),
 		exceptionsPipe:      make(chan *protocol.Exception),
 		runnerExceptionPipe: make(chan *protocol.Exception),
 		exceptions:          make([]*protocol.Exception, 0, 0),
+		exceptionGroups:     make(map[string]*protocol.Exception),
 		closeCmd:            make(chan struct{}),
 		stopped:             make(chan struct{}),
 		running:             make(chan struct{}),
 		labels:              make(map[string]interface{}),
 		labelsPipe:          make(chan epsagonLabel),
+		metrics:             make(map[string]*aggregatedMetric),
+		metricsPipe:         make(chan epsagonMetric),
+		logsPipe:            make(chan LogRecord),
+		logs:                make([]LogRecord, 0),
 	}
 	if config.Debug {
-		log.Println("EPSAGON DEBUG: Created a new tracer")
+		config.GetLogger().Debugf("Created a new tracer")
 	}
 	return tracer
 }
@@ -436,7 +503,7 @@ func CreateGlobalTracer(config *Config) Tracer {
 	mutex.Lock()
 	defer mutex.Unlock()
 	if GlobalTracer != nil && !GlobalTracer.Stopped() {
-		log.Println("The tracer is already created, Closing and Creating.")
+		config.GetLogger().Infof("The tracer is already created, Closing and Creating.")
 		StopGlobalTracer()
 	}
 	GlobalTracer = CreateTracer(config)
@@ -448,22 +515,28 @@ func (tracer *epsagonTracer) AddException(exception *protocol.Exception) {
 	defer func() {
 		recover()
 	}()
-	tracer.exceptionsPipe <- exception
+	select {
+	case tracer.exceptionsPipe <- exception:
+	case <-tracer.stopped:
+	}
 }
 
 // AddEvent adds an event to the tracer
 func (tracer *epsagonTracer) AddEvent(event *protocol.Event) {
 	if tracer.Config.Debug {
-		log.Println("EPSAGON DEBUG: Adding event: ", event)
+		tracer.Config.GetLogger().Debugf("Adding event: %v", event)
+	}
+	select {
+	case tracer.eventsPipe <- event:
+	case <-tracer.stopped:
 	}
-	tracer.eventsPipe <- event
 }
 
 // AddEvent adds an event to the tracer
 func AddEvent(event *protocol.Event) {
 	if GlobalTracer == nil || GlobalTracer.Stopped() {
 		// TODO
-		log.Println("The tracer is not initialized!")
+		DefaultLogger(false).Warnf("The tracer is not initialized!")
 		return
 	}
 	GlobalTracer.AddEvent(event)
@@ -478,7 +551,7 @@ func (tracer *epsagonTracer) verifyLabel(label epsagonLabel) bool {
 		valueSize = len(label.value.(string))
 	default:
 		if tracer.Config.Debug {
-			log.Println("EPSAGON DEBUG: Supported label types are: int, float, string, bool")
+			tracer.Config.GetLogger().Debugf("Supported label types are: int, float, string, bool")
 		}
 		return false
 	}
@@ -493,16 +566,19 @@ func (tracer *epsagonTracer) verifyLabel(label epsagonLabel) bool {
 // AddLabel adds a label to the tracer
 func (tracer *epsagonTracer) AddLabel(key string, value interface{}) {
 	if tracer.Config.Debug {
-		log.Println("EPSAGON DEBUG: Adding label: ", key, value)
+		tracer.Config.GetLogger().Debugf("Adding label: %s %v", key, value)
 	}
 	label := epsagonLabel{key, value}
-	tracer.labelsPipe <- label
+	select {
+	case tracer.labelsPipe <- label:
+	case <-tracer.stopped:
+	}
 }
 
 // AddLabel adds a label to the tracer
 func AddLabel(key string, value interface{}) {
 	if GlobalTracer == nil || GlobalTracer.Stopped() {
-		log.Println("The tracer is not initialized!")
+		DefaultLogger(false).Warnf("The tracer is not initialized!")
 		return
 	}
 	GlobalTracer.AddLabel(key, value)
@@ -512,12 +588,12 @@ func AddLabel(key string, value interface{}) {
 func AddException(exception *protocol.Exception) {
 	defer func() {
 		if r := recover(); r != nil {
-			log.Println("Epsagon: Failed to add exception")
+			DefaultLogger(false).Warnf("Failed to add exception")
 		}
 	}()
 	if GlobalTracer == nil || GlobalTracer.Stopped() {
 		// TODO
-		log.Println("The tracer is not initialized!")
+		DefaultLogger(false).Warnf("The tracer is not initialized!")
 		return
 	}
 	GlobalTracer.AddException(exception)
@@ -525,7 +601,10 @@ func AddException(exception *protocol.Exception) {
 
 // Stop stops the tracer running routine
 func (tracer *epsagonTracer) SendStopSignal() {
-	tracer.closeCmd <- struct{}{}
+	select {
+	case tracer.closeCmd <- struct{}{}:
+	case <-tracer.stopped:
+	}
 }
 
 // Stop stops the tracer running routine, waiting for the tracer to finish
@@ -543,7 +622,7 @@ func (tracer *epsagonTracer) Stop() {
 func StopGlobalTracer() {
 	if GlobalTracer == nil || GlobalTracer.Stopped() {
 		// TODO
-		log.Println("The tracer is not initialized!")
+		DefaultLogger(false).Warnf("The tracer is not initialized!")
 		return
 	}
 	GlobalTracer.Stop()
@@ -553,7 +632,7 @@ func StopGlobalTracer() {
 // run until it
 func (tracer *epsagonTracer) Run() {
 	if tracer.Config.Debug {
-		log.Println("EPSAGON DEBUG: tracer started running")
+		tracer.Config.GetLogger().Debugf("tracer started running")
 	}
 	if tracer.Running() {
 		return
@@ -567,16 +646,20 @@ func (tracer *epsagonTracer) Run() {
 		case event := <-tracer.eventsPipe:
 			tracer.events = append(tracer.events, event)
 		case exception := <-tracer.exceptionsPipe:
-			tracer.exceptions = append(tracer.exceptions, exception)
+			tracer.recordException(exception)
 		case exception := <-tracer.runnerExceptionPipe:
 			tracer.runnerException = exception
 		case label := <-tracer.labelsPipe:
 			if tracer.verifyLabel(label) {
 				tracer.labels[label.key] = label.value
 			}
+		case metric := <-tracer.metricsPipe:
+			tracer.aggregateMetric(metric)
+		case record := <-tracer.logsPipe:
+			tracer.captureLog(record)
 		case <-tracer.closeCmd:
 			if tracer.Config.Debug {
-				log.Println("EPSAGON DEBUG: tracer stops running, sending traces")
+				tracer.Config.GetLogger().Debugf("tracer stops running, sending traces")
 			}
 			tracer.sendTraces()
 			return
@@ -596,21 +679,11 @@ func GetGlobalTracerConfig() *Config {
 	return GlobalTracer.GetConfig()
 }
 
-func createException(exceptionType, msg string) *protocol.Exception {
-	stack := debug.Stack()
-	return &protocol.Exception{
-		Type:      exceptionType,
-		Message:   msg,
-		Traceback: string(stack),
-		Time:      GetTimestamp(),
-	}
-}
-
 // AddExceptionTypeAndMessage adds an exception to the current tracer with
 // the current stack and time.
 // exceptionType, msg are strings that will be added to the exception
 func (tracer *epsagonTracer) AddExceptionTypeAndMessage(exceptionType, msg string) {
-	tracer.AddException(createException(exceptionType, msg))
+	tracer.AddException(NewTracerException(tracer, exceptionType, msg, nil))
 }
 
 func (tracer *epsagonTracer) AddError(errorType string, value interface{}) {
@@ -622,13 +695,16 @@ func (tracer *epsagonTracer) AddError(errorType string, value interface{}) {
 		message = value.(error).Error()
 	default:
 		if tracer.Config.Debug {
-			log.Println("EPSAGON DEBUG: Supported error types are: string, error")
+			tracer.Config.GetLogger().Debugf("Supported error types are: string, error")
 		}
 		return
 	}
 	if tracer.Config.Debug {
-		log.Println("EPSAGON DEBUG: Adding error message to trace: ", message)
+		tracer.Config.GetLogger().Debugf("Adding error message to trace: %s", message)
+	}
+	exception := NewTracerException(tracer, errorType, message, value)
+	select {
+	case tracer.runnerExceptionPipe <- exception:
+	case <-tracer.stopped:
 	}
-	exception := createException(errorType, message)
-	tracer.runnerExceptionPipe <- exception
 }
@@ -188,3 +188,30 @@ func Test_MaxTraceSize_sanity(t *testing.T) {
 	trace = testWithTracer(timeout, func() { epsagon.Label("1", "2") })
 	Expect(trace).To(BeNil())
 }
+
+// basicTracer implements only the methods of the Tracer interface
+type basicTracer struct {
+	tracer.Tracer
+}
+
+var _ = Describe("Optional tracer interfaces", func() {
+	It("Uses the optional methods of tracers that implement them", func() {
+		mockedTracer := &tracer.MockedEpsagonTracer{TraceID: "trace-id"}
+		tracer.AddTracerMetric(mockedTracer, tracer.CounterMetric, "requests", 1, "")
+		tracer.AddTracerLog(mockedTracer, tracer.NewLogRecord(time.Now(), "info", "handling"))
+		Expect(mockedTracer.Metrics).To(HaveKeyWithValue("requests", 1.0))
+		Expect(mockedTracer.Logs).To(HaveLen(1))
+		Expect(tracer.GetTracerTraceID(mockedTracer)).To(Equal("trace-id"))
+	})
+
+	It("Ignores the optional methods of tracers that do not implement them", func() {
+		mockedTracer := &tracer.MockedEpsagonTracer{TraceID: "trace-id"}
+		currentTracer := basicTracer{Tracer: mockedTracer}
+		tracer.AddTracerMetric(currentTracer, tracer.CounterMetric, "requests", 1, "")
+		tracer.AddTracerLog(currentTracer, tracer.NewLogRecord(time.Now(), "info", "handling"))
+		Expect(mockedTracer.Metrics).To(BeEmpty())
+		Expect(mockedTracer.Logs).To(BeEmpty())
+		Expect(tracer.GetTracerTraceID(currentTracer)).To(BeEmpty())
+		Expect(tracer.GetTracerTraceID(nil)).To(BeEmpty())
+	})
+})
@@ -9,7 +9,6 @@ import (
 	"github.com/epsagon/epsagon-go/epsagon"
 	"github.com/epsagon/epsagon-go/protocol"
 	"github.com/epsagon/epsagon-go/tracer"
-	"log"
 	"time"
 )
 
@@ -23,6 +22,9 @@ func WrapSession(s *session.Session, args ...context.Context) *session.Session {
 			Name: "github.com/epsagon/epsagon-go/wrappers/aws/aws-sdk-go/aws/aws.go",
 			Fn: func(r *request.Request) {
 				currentTracer := epsagon.ExtractTracer(args)
+				if currentTracer == nil || currentTracer.GetConfig().IntegrationDisabled(tracer.AWSSDKIntegration) {
+					return
+				}
 				completeEventData(r, currentTracer)
 			},
 		})
@@ -35,16 +37,17 @@ func getTimeStampFromRequest(r *request.Request) float64 {
 
 func completeEventData(r *request.Request, currentTracer tracer.Tracer) {
 	defer epsagon.GeneralEpsagonRecover("aws-sdk-go wrapper", "", currentTracer)
-	if currentTracer.GetConfig().Debug {
-		log.Printf("EPSAGON DEBUG OnComplete current tracer: %+v\n", currentTracer)
-		log.Printf("EPSAGON DEBUG OnComplete request response: %+v\n", r.HTTPResponse)
-		log.Printf("EPSAGON DEBUG OnComplete request Operation: %+v\n", r.Operation)
-		log.Printf("EPSAGON DEBUG OnComplete request ClientInfo: %+v\n", r.ClientInfo)
-		log.Printf("EPSAGON DEBUG OnComplete request Params: %+v\n", r.Params)
-		log.Printf("EPSAGON DEBUG OnComplete request Data: %+v\n", r.Data)
+	if config := currentTracer.GetConfig(); config.Debug {
+		logger := config.GetLogger()
+		logger.Debugf("OnComplete current tracer: %+v", currentTracer)
+		logger.Debugf("OnComplete request response: %+v", r.HTTPResponse)
+		logger.Debugf("OnComplete request Operation: %+v", r.Operation)
+		logger.Debugf("OnComplete request ClientInfo: %+v", r.ClientInfo)
+		logger.Debugf("OnComplete request Params: %+v", r.Params)
+		logger.Debugf("OnComplete request Data: %+v", r.Data)
 	}
 
-	endTime := tracer.GetTimestamp()
+	endTime := tracer.GetTracerTimestamp(currentTracer)
 	event := protocol.Event{
 		Id:        r.RequestID,
 		StartTime: getTimeStampFromRequest(r),
@@ -86,24 +89,25 @@ func extractResourceInformation(
 
 func defaultFactory(r *request.Request, res *protocol.Resource, metadataOnly bool, currentTracer tracer.Tracer) {
 	if currentTracer.GetConfig().Debug {
-		log.Println("EPSAGON DEBUG:: entering defaultFactory")
+		currentTracer.GetConfig().GetLogger().Debugf("entering defaultFactory")
 	}
 	if !metadataOnly {
-		extractInterfaceToMetadata(r.Data, res)
-		extractInterfaceToMetadata(r.Params, res)
+		logger := currentTracer.GetConfig().GetLogger()
+		extractInterfaceToMetadata(r.Data, res, logger)
+		extractInterfaceToMetadata(r.Params, res, logger)
 	}
 }
 
-func extractInterfaceToMetadata(input interface{}, res *protocol.Resource) {
+func extractInterfaceToMetadata(input interface{}, res *protocol.Resource, logger tracer.Logger) {
 	var data map[string]interface{}
 	rawJSON, err := json.Marshal(input)
 	if err != nil {
-		log.Printf("EPSAGON DEBUG: Failed to marshal input: %+v\n", input)
+		logger.Debugf("Failed to marshal input: %+v", input)
 		return
 	}
 	err = json.Unmarshal(rawJSON, &data)
 	if err != nil {
-		log.Printf("EPSAGON DEBUG: Failed to unmarshal input: %+v\n", rawJSON)
+		logger.Debugf("Failed to unmarshal input: %+v", rawJSON)
 		return
 	}
 	for key, value := range data {
@@ -3,7 +3,6 @@ package epsagonfiber
 import (
 	"encoding/json"
 	"fmt"
-	"runtime/debug"
 	"strconv"
 
 	"github.com/epsagon/epsagon-go/epsagon"
@@ -34,12 +33,7 @@ func convertMapValuesToString(
 	errorMessage string) string {
 	processed, err := json.Marshal(values)
 	if err != nil {
-		wrapperTracer.AddException(&protocol.Exception{
-			Type:      "trigger-creation",
-			Message:   errorMessage,
-			Traceback: string(debug.Stack()),
-			Time:      tracer.GetTimestamp(),
-		})
+		wrapperTracer.AddException(tracer.NewTracerException(wrapperTracer, "trigger-creation", errorMessage, err))
 		return ""
 	}
 	return string(processed)
@@ -102,7 +96,7 @@ func CreateHTTPTriggerEvent(wrapperTracer tracer.Tracer, fiberCtx *fiber.Ctx, re
 	event := &protocol.Event{
 		Id:        "",
 		Origin:    "trigger",
-		StartTime: tracer.GetTimestamp(),
+		StartTime: tracer.GetTracerTimestamp(wrapperTracer),
 		Resource: &protocol.Resource{
 			Name:      name,
 			Type:      "http",
@@ -131,6 +125,9 @@ func (middleware *FiberEpsagonMiddleware) HandlerFunc() fiber.Handler {
 		config = &epsagon.Config{}
 	}
 	return func(c *fiber.Ctx) (err error) {
+		if config.IntegrationDisabled(tracer.FiberIntegration) {
+			return fiberHandler(c)
+		}
 		if epsagon.ShouldIgnoreRequest(getRequestContentType(c), c.Path()) {
 			return c.Next()
 		}
@@ -157,7 +154,12 @@ func (middleware *FiberEpsagonMiddleware) HandlerFunc() fiber.Handler {
 		wrapperTracer.Start()
 		defer wrapperTracer.SendStopSignal()
 		userContext := c.UserContext()
-		c.SetUserContext(epsagon.ContextWithTracer(wrapperTracer, userContext))
+		requestID := epsagon.ServerRequestID(c.Get(epsagon.RequestIDHeader), wrapperTracer)
+		c.SetUserContext(epsagon.ContextWithRequestID(
+			epsagon.ContextWithTracer(wrapperTracer, userContext), requestID))
+		if traceIDHeader := wrapperTracer.GetConfig().TraceIDHeader; len(traceIDHeader) > 0 {
+			c.Set(traceIDHeader, tracer.GetTracerTraceID(wrapperTracer))
+		}
 		triggerEvent = CreateHTTPTriggerEvent(wrapperTracer, c, c.Hostname())
 		wrapperTracer.AddEvent(triggerEvent)
 		wrapper := epsagon.WrapGenericFunction(
@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"strings"
 	"testing"
 	"time"
@@ -82,6 +83,49 @@ var _ = Describe("fiber_middleware", func() {
 				verifyResponseSuccess(resp, err)
 				Expect(called).To(Equal(true))
 			})
+			It("Passes requests through when the integration is disabled", func() {
+				config.DisabledIntegrations = []string{tracer.FiberIntegration}
+				app = fiber.New()
+				app.Use((&FiberEpsagonMiddleware{Config: config}).HandlerFunc())
+				app.Get(SanityPath, func(c *fiber.Ctx) error {
+					called = true
+					return c.SendString(ResponseData)
+				})
+				resp, err := app.Test(request)
+				verifyResponseSuccess(resp, err)
+				Expect(called).To(Equal(true))
+				Expect(events).To(BeEmpty())
+			})
+			It("Checks whether the integration is disabled on every request", func() {
+				os.Setenv(tracer.DisabledIntegrationsEnvVar, tracer.FiberIntegration)
+				defer os.Unsetenv(tracer.DisabledIntegrationsEnvVar)
+				resp, err := app.Test(request)
+				verifyResponseSuccess(resp, err)
+				Expect(called).To(Equal(true))
+				Expect(events).To(BeEmpty())
+
+				os.Unsetenv(tracer.DisabledIntegrationsEnvVar)
+				resp, err = app.Test(httptest.NewRequest(SanityHTTPMethod, SanityPath, nil))
+				verifyResponseSuccess(resp, err)
+				Expect(events).NotTo(BeEmpty())
+			})
+			It("Exposes the trace and request IDs", func() {
+				config.TraceIDHeader = "X-Epsagon-Trace-Id"
+				tracer.GlobalTracer.(*tracer.MockedEpsagonTracer).TraceID = "test-trace-id"
+				request = httptest.NewRequest(SanityHTTPMethod, "/ids", nil)
+				request.Header.Set(epsagon.RequestIDHeader, "test-request-id")
+				var traceID, requestID string
+				app.Get("/ids", func(c *fiber.Ctx) error {
+					traceID = epsagon.TraceID(c.UserContext())
+					requestID = epsagon.RequestID(c.UserContext())
+					return c.SendString(ResponseData)
+				})
+				resp, err := app.Test(request)
+				verifyResponseSuccess(resp, err)
+				Expect(traceID).To(Equal("test-trace-id"))
+				Expect(requestID).To(Equal("test-request-id"))
+				Expect(resp.Header.Get("X-Epsagon-Trace-Id")).To(Equal("test-trace-id"))
+			})
 			It("creates a runner & trigger events", func() {
 				eventsRecievedChan := make(chan bool)
 				tracer.GlobalTracer = &tracer.MockedEpsagonTracer{
@@ -15,9 +15,21 @@ import (
 // TracerKey is the key of the epsagon tracer in the gin.Context Keys map passed to the handlers
 const TracerKey = "EpsagonTracer"
 
-// EpsagonContext creates a context.Background() with epsagon's associated tracer for nexted instrumentations
+// RequestIDKey is the key of the request ID in the gin.Context Keys map passed to the handlers
+const RequestIDKey = "EpsagonRequestID"
+
+// EpsagonContext creates a context.Background() with epsagon's associated tracer for nexted instrumentations.
+// When the handler is not traced, e.g. the gin integration is disabled, the global tracer is used
 func EpsagonContext(c *gin.Context) context.Context {
-	return epsagon.ContextWithTracer(c.Keys[TracerKey].(tracer.Tracer))
+	wrapperTracer, ok := c.Keys[TracerKey].(tracer.Tracer)
+	if !ok {
+		if tracer.GlobalTracer == nil {
+			return context.Background()
+		}
+		wrapperTracer = tracer.GlobalTracer
+	}
+	return epsagon.ContextWithRequestID(
+		epsagon.ContextWithTracer(wrapperTracer), c.GetString(RequestIDKey))
 }
 
 // GinRouterWrapper is an epsagon instumentation wrapper for gin.RouterGroup
@@ -63,18 +75,27 @@ func wrapGinHandler(handler gin.HandlerFunc, hostname string, relativePath strin
 		config = &epsagon.Config{}
 	}
 	return func(c *gin.Context) {
+		if config.IntegrationDisabled(tracer.GinIntegration) {
+			handler(c)
+			return
+		}
 		wrapperTracer := tracer.CreateTracer(&config.Config)
 		wrapperTracer.Start()
 		defer wrapperTracer.SendStopSignal()
 
 		c.Set(TracerKey, wrapperTracer)
+		c.Set(RequestIDKey, epsagon.ServerRequestID(
+			c.GetHeader(epsagon.RequestIDHeader), wrapperTracer))
+		if traceIDHeader := wrapperTracer.GetConfig().TraceIDHeader; len(traceIDHeader) > 0 {
+			c.Header(traceIDHeader, tracer.GetTracerTraceID(wrapperTracer))
+		}
 		wrapper := epsagon.WrapGenericFunction(
 			handler, config, wrapperTracer, false, relativePath,
 		)
 		triggerEvent := epsagonhttp.CreateHTTPTriggerEvent(
 			wrapperTracer, c.Request, hostname)
 		wrapperTracer.AddEvent(triggerEvent)
-		if !config.MetadataOnly {
+		if !wrapperTracer.GetConfig().MetadataOnly {
 			wrapGinWriter(c, triggerEvent)
 		}
 		defer postExecutionUpdates(wrapperTracer, triggerEvent, c, wrapper)
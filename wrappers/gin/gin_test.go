@@ -2,9 +2,11 @@ package epsagongin
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http/httptest"
+	"os"
 	"testing"
 	"time"
 
@@ -93,6 +95,63 @@ var _ = Describe("gin_wrapper", func() {
 					tracer.GlobalTracer.(*tracer.MockedEpsagonTracer).Labels["test"],
 				).To(Equal("ok"))
 			})
+			It("Passes requests through when the integration is disabled", func() {
+				wrapper.Config.DisabledIntegrations = []string{tracer.GinIntegration}
+				mockedEngine.TestHandler = func(handler gin.HandlerFunc) {
+					handler(testGinContext)
+					Expect(called).To(Equal(true))
+				}
+				wrapper.GET("/test", func(c *gin.Context) {
+					Expect(c.Keys).NotTo(HaveKey(TracerKey))
+					called = true
+				})
+				Expect(events).To(BeEmpty())
+			})
+			It("Checks whether the integration is disabled on every request", func() {
+				var handler gin.HandlerFunc
+				mockedEngine.TestHandler = func(wrapped gin.HandlerFunc) {
+					handler = wrapped
+				}
+				wrapper.GET("/test", func(c *gin.Context) { called = true })
+				os.Setenv(tracer.DisabledIntegrationsEnvVar, tracer.GinIntegration)
+				defer os.Unsetenv(tracer.DisabledIntegrationsEnvVar)
+				handler(testGinContext)
+				Expect(called).To(Equal(true))
+				Expect(events).To(BeEmpty())
+
+				os.Unsetenv(tracer.DisabledIntegrationsEnvVar)
+				handler(testGinContext)
+				Expect(events).NotTo(BeEmpty())
+			})
+			It("Creates a context with the global tracer when the integration is disabled", func() {
+				wrapper.Config.DisabledIntegrations = []string{tracer.GinIntegration}
+				mockedEngine.TestHandler = func(handler gin.HandlerFunc) {
+					handler(testGinContext)
+					Expect(called).To(Equal(true))
+				}
+				wrapper.GET("/test", func(c *gin.Context) {
+					ctx := EpsagonContext(c)
+					Expect(epsagon.ExtractTracer([]context.Context{ctx})).To(Equal(tracer.GlobalTracer))
+					called = true
+				})
+			})
+			It("Exposes the trace and request IDs", func() {
+				mockedTracer := tracer.GlobalTracer.(*tracer.MockedEpsagonTracer)
+				mockedTracer.Config.TraceIDHeader = "X-Epsagon-Trace-Id"
+				mockedTracer.TraceID = "test-trace-id"
+				testGinContext.Request.Header.Set(epsagon.RequestIDHeader, "test-request-id")
+				mockedEngine.TestHandler = func(handler gin.HandlerFunc) {
+					handler(testGinContext)
+					Expect(called).To(Equal(true))
+				}
+				wrapper.GET("/test", func(c *gin.Context) {
+					ctx := EpsagonContext(c)
+					Expect(epsagon.TraceID(ctx)).To(Equal("test-trace-id"))
+					Expect(epsagon.RequestID(ctx)).To(Equal("test-request-id"))
+					called = true
+				})
+				Expect(testGinContext.Writer.Header().Get("X-Epsagon-Trace-Id")).To(Equal("test-trace-id"))
+			})
 			It("Creates a runner and trigger events for handler invocation", func() {
 				config := &epsagon.Config{Config: tracer.Config{
 					Disable:  true,
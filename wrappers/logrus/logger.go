@@ -0,0 +1,16 @@
+package epsagonlogrus
+
+import (
+	"github.com/epsagon/epsagon-go/tracer"
+	"github.com/sirupsen/logrus"
+)
+
+// ComponentKey is the field added to Epsagon's internal diagnostics logged with NewLogger
+const ComponentKey = "component"
+
+// NewLogger creates a tracer.Logger that writes Epsagon's internal
+// diagnostics to the given logrus logger, with an "epsagon" component field
+// config.Logger = epsagonlogrus.NewLogger(logrus.StandardLogger())
+func NewLogger(logger logrus.FieldLogger) tracer.Logger {
+	return logger.WithField(ComponentKey, "epsagon")
+}
@@ -0,0 +1,65 @@
+package epsagonlogrus
+
+import (
+	"context"
+
+	"github.com/epsagon/epsagon-go/epsagon"
+	"github.com/epsagon/epsagon-go/tracer"
+	"github.com/sirupsen/logrus"
+)
+
+// Options configures the Epsagon logrus hook
+type Options struct {
+	// CaptureLogs attaches the last log lines to the runner event of the trace
+	CaptureLogs bool
+	// CaptureLevel is the least severe level of captured log lines, defaults to logrus.InfoLevel
+	// when nil (logrus.PanicLevel is the zero logrus.Level)
+	CaptureLevel *logrus.Level
+}
+
+// Hook is a logrus.Hook that adds the current trace ID to every log entry
+// and optionally captures the log lines in the trace
+type Hook struct {
+	ctx          context.Context
+	options      Options
+	captureLevel logrus.Level
+}
+
+// NewHook creates an Epsagon logrus hook. The tracer is taken from the
+// entry context (logger.WithContext), the given context or the global tracer
+// logger.AddHook(epsagonlogrus.NewHook(nil))
+func NewHook(options *Options, ctx ...context.Context) *Hook {
+	hook := &Hook{captureLevel: logrus.InfoLevel}
+	if options != nil {
+		hook.options = *options
+	}
+	if hook.options.CaptureLevel != nil {
+		hook.captureLevel = *hook.options.CaptureLevel
+	}
+	if len(ctx) > 0 {
+		hook.ctx = ctx[0]
+	}
+	return hook
+}
+
+// Levels implements logrus.Hook
+func (hook *Hook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements logrus.Hook
+func (hook *Hook) Fire(entry *logrus.Entry) error {
+	ctx := entry.Context
+	if ctx == nil {
+		ctx = hook.ctx
+	}
+	currentTracer := epsagon.TracerFromContext(ctx)
+	if currentTracer == nil {
+		return nil
+	}
+	entry.Data[epsagon.TraceIDLogKey] = tracer.GetTracerTraceID(currentTracer)
+	if hook.options.CaptureLogs && entry.Level <= hook.captureLevel {
+		tracer.AddTracerLog(currentTracer, tracer.NewLogRecord(entry.Time, entry.Level.String(), entry.Message))
+	}
+	return nil
+}
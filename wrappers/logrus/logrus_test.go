@@ -0,0 +1,108 @@
+package epsagonlogrus_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/epsagon/epsagon-go/epsagon"
+	"github.com/epsagon/epsagon-go/protocol"
+	"github.com/epsagon/epsagon-go/tracer"
+	epsagonlogrus "github.com/epsagon/epsagon-go/wrappers/logrus"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+)
+
+func TestLogrusWrapper(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Logrus Wrapper")
+}
+
+var _ = Describe("Logrus hook", func() {
+	var (
+		events     []*protocol.Event
+		output     *bytes.Buffer
+		logger     *logrus.Logger
+		ctx        context.Context
+		tracerMock *tracer.MockedEpsagonTracer
+	)
+
+	BeforeEach(func() {
+		events = make([]*protocol.Event, 0)
+		tracerMock = &tracer.MockedEpsagonTracer{
+			Events:  &events,
+			TraceID: "test-trace-id",
+			Config:  &tracer.Config{Disable: true, TestMode: true},
+		}
+		ctx = epsagon.ContextWithTracer(tracerMock)
+		output = &bytes.Buffer{}
+		logger = logrus.New()
+		logger.SetOutput(output)
+		logger.SetFormatter(&logrus.JSONFormatter{})
+	})
+
+	It("Adds the trace ID to every entry", func() {
+		logger.AddHook(epsagonlogrus.NewHook(nil))
+		logger.WithContext(ctx).Info("hello")
+		Expect(output.String()).To(ContainSubstring(`"epsagon.trace_id":"test-trace-id"`))
+		Expect(tracerMock.Logs).To(BeEmpty())
+	})
+
+	It("Captures entries at or above the capture level", func() {
+		captureLevel := logrus.WarnLevel
+		logger.AddHook(epsagonlogrus.NewHook(&epsagonlogrus.Options{
+			CaptureLogs:  true,
+			CaptureLevel: &captureLevel,
+		}, ctx))
+		logger.Info("info line")
+		logger.Warn("warn line")
+		logger.Error("error line")
+		Expect(tracerMock.Logs).To(HaveLen(2))
+		Expect(tracerMock.Logs[0].Level).To(Equal("warning"))
+		Expect(tracerMock.Logs[0].Message).To(Equal("warn line"))
+		Expect(tracerMock.Logs[1].Message).To(Equal("error line"))
+	})
+
+	It("Captures entries from the info level by default", func() {
+		logger.AddHook(epsagonlogrus.NewHook(&epsagonlogrus.Options{CaptureLogs: true}, ctx))
+		logger.Debug("debug line")
+		logger.Info("info line")
+		Expect(tracerMock.Logs).To(HaveLen(1))
+		Expect(tracerMock.Logs[0].Message).To(Equal("info line"))
+	})
+
+	It("Captures only panic entries at the panic level", func() {
+		captureLevel := logrus.PanicLevel
+		logger.AddHook(epsagonlogrus.NewHook(&epsagonlogrus.Options{
+			CaptureLogs:  true,
+			CaptureLevel: &captureLevel,
+		}, ctx))
+		logger.Error("error line")
+		Expect(func() { logger.Panic("panic line") }).To(Panic())
+		Expect(tracerMock.Logs).To(HaveLen(1))
+		Expect(tracerMock.Logs[0].Message).To(Equal("panic line"))
+	})
+
+	It("Leaves entries untouched without a tracer", func() {
+		logger.AddHook(epsagonlogrus.NewHook(&epsagonlogrus.Options{CaptureLogs: true}))
+		logger.Info("hello")
+		Expect(output.String()).NotTo(ContainSubstring(epsagon.TraceIDLogKey))
+	})
+})
+
+var _ = Describe("Logrus logger", func() {
+	It("Writes the tracer diagnostics at their level", func() {
+		output := &bytes.Buffer{}
+		logrusLogger := logrus.New()
+		logrusLogger.SetOutput(output)
+		logrusLogger.SetFormatter(&logrus.JSONFormatter{})
+		logger := epsagonlogrus.NewLogger(logrusLogger)
+		logger.Debugf("hidden")
+		logger.Errorf("failed sending %d traces", 1)
+		Expect(output.String()).NotTo(ContainSubstring("hidden"))
+		Expect(output.String()).To(ContainSubstring(`"level":"error"`))
+		Expect(output.String()).To(ContainSubstring(`"msg":"failed sending 1 traces"`))
+		Expect(output.String()).To(ContainSubstring(`"component":"epsagon"`))
+	})
+})
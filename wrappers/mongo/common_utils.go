@@ -7,7 +7,6 @@ import (
 	"github.com/epsagon/epsagon-go/epsagon"
 	"github.com/epsagon/epsagon-go/protocol"
 	"github.com/epsagon/epsagon-go/tracer"
-	"github.com/google/uuid"
 	"go.mongodb.org/mongo-driver/mongo"
 	"strconv"
 
@@ -35,17 +34,17 @@ func currentFuncName() string {
 func startMongoEvent(opName string, coll *MongoCollectionWrapper) *protocol.Event {
 	defer epsagon.GeneralEpsagonRecover("mongo-driver", currentFuncName(), coll.tracer)
 	return &protocol.Event{
-		Id:        "mongodb-" + uuid.New().String(),
+		Id:        "mongodb-" + tracer.NewTracerID(coll.tracer),
 		Origin:    "mongodb",
 		ErrorCode: protocol.ErrorCode_OK,
-		StartTime: tracer.GetTimestamp(),
+		StartTime: tracer.GetTracerTimestamp(coll.tracer),
 		Resource:  createMongoResource(opName, coll),
 	}
 }
 
 func completeMongoEvent(currentTracer tracer.Tracer, event *protocol.Event) {
 	defer epsagon.GeneralEpsagonRecover("mongo-driver", currentFuncName(), currentTracer)
-	event.Duration = tracer.GetTimestamp() - event.StartTime
+	event.Duration = tracer.GetTracerTimestamp(currentTracer) - event.StartTime
 	currentTracer.AddEvent(event)
 
 }
@@ -96,7 +95,7 @@ func marshalToMetadata(
 ) {
 	docBytes, err := json.Marshal(s)
 	if err != nil {
-		epsagon.DebugLog(config.Debug, "Could not Marshal JSON", err)
+		config.GetLogger().Debugf("Could not Marshal JSON: %v", err)
 	}
 	docString := string(docBytes)
 	if docString == "" {
@@ -113,9 +112,13 @@ func readCursor(cursor *mongo.Cursor) ([]map[string]string, error) {
 	return documents, err
 }
 
-func logOperationFailure(messages ...string) {
+func logOperationFailure(currentTracer tracer.Tracer, messages ...string) {
+	if currentTracer == nil {
+		return
+	}
+	logger := currentTracer.GetConfig().GetLogger()
 	for _, m := range messages {
-		epsagon.DebugLog(true, "[MONGO]", m)
+		logger.Warnf("[MONGO] %s", m)
 	}
 }
 
@@ -14,14 +14,18 @@ import (
 type MongoCollectionWrapper struct {
 	collection *mongo.Collection
 	tracer     tracer.Tracer
+	disabled   bool
 }
 
 func WrapMongoCollection(
 	collection *mongo.Collection, ctx ...context.Context,
 ) *MongoCollectionWrapper {
+	currentTracer := epsagon.ExtractTracer(ctx)
 	return &MongoCollectionWrapper{
 		collection: collection,
-		tracer:     epsagon.ExtractTracer(ctx),
+		tracer:     currentTracer,
+		disabled: currentTracer == nil ||
+			currentTracer.GetConfig().IntegrationDisabled(tracer.MongoIntegration),
 	}
 }
 
@@ -34,12 +38,15 @@ func (coll *MongoCollectionWrapper) Database() *mongo.Database {
 }
 
 func (coll *MongoCollectionWrapper) Clone(opts ...*mongoOptions.CollectionOptions) (interface{}, error) {
+	if coll.disabled {
+		return coll.collection.Clone(opts...)
+	}
 	event := startMongoEvent("Clone", coll)
 	response, err := coll.collection.Clone(
 		opts...,
 	)
 	if err != nil {
-		logOperationFailure(fmt.Sprint("Could not complete Clone"), err.Error())
+		logOperationFailure(coll.tracer, fmt.Sprint("Could not complete Clone"), err.Error())
 		coll.tracer.AddExceptionTypeAndMessage(
 			"mongo-driver",
 			err.Error(),
@@ -54,9 +61,10 @@ func (coll *MongoCollectionWrapper) Clone(opts ...*mongoOptions.CollectionOption
 func (coll *MongoCollectionWrapper) InsertOne(
 	ctx context.Context, document interface{}, opts ...*mongoOptions.InsertOneOptions,
 ) (*mongo.InsertOneResult, error) {
+	if coll.disabled {
+		return coll.collection.InsertOne(ctx, document, opts...)
+	}
 	event := startMongoEvent("InsertOne", coll)
-	fmt.Println("EVENT::::")
-	fmt.Println(event)
 	response, err := coll.collection.InsertOne(
 		ctx,
 		document,
@@ -64,7 +72,7 @@ func (coll *MongoCollectionWrapper) InsertOne(
 	)
 
 	if err != nil {
-		logOperationFailure(fmt.Sprint("Could not complete InsertOne"), err.Error())
+		logOperationFailure(coll.tracer, fmt.Sprint("Could not complete InsertOne"), err.Error())
 		coll.tracer.AddExceptionTypeAndMessage(
 			"mongo-driver",
 			err.Error(),
@@ -84,6 +92,9 @@ func (coll *MongoCollectionWrapper) InsertOne(
 func (coll *MongoCollectionWrapper) InsertMany(
 	ctx context.Context, documents []interface{}, opts ...*mongoOptions.InsertManyOptions,
 ) (*mongo.InsertManyResult, error) {
+	if coll.disabled {
+		return coll.collection.InsertMany(ctx, documents, opts...)
+	}
 	event := startMongoEvent("InsertMany", coll)
 	response, err := coll.collection.InsertMany(
 		ctx,
@@ -91,7 +102,7 @@ func (coll *MongoCollectionWrapper) InsertMany(
 		opts...,
 	)
 	if err != nil {
-		logOperationFailure(fmt.Sprintf("Could not complete %s", "InsertMany"), err.Error())
+		logOperationFailure(coll.tracer, fmt.Sprintf("Could not complete %s", "InsertMany"), err.Error())
 		coll.tracer.AddExceptionTypeAndMessage(
 			"mongo-driver",
 			err.Error(),
@@ -111,6 +122,9 @@ func (coll *MongoCollectionWrapper) InsertMany(
 func (coll *MongoCollectionWrapper) BulkWrite(
 	ctx context.Context, models []mongo.WriteModel, opts ...*mongoOptions.BulkWriteOptions,
 ) (*mongo.BulkWriteResult, error) {
+	if coll.disabled {
+		return coll.collection.BulkWrite(ctx, models, opts...)
+	}
 	event := startMongoEvent("BulkWrite", coll)
 	response, err := coll.collection.BulkWrite(
 		ctx,
@@ -118,7 +132,7 @@ func (coll *MongoCollectionWrapper) BulkWrite(
 		opts...,
 	)
 	if err != nil {
-		logOperationFailure(fmt.Sprint("Could not complete BulkWrite"), err.Error())
+		logOperationFailure(coll.tracer, fmt.Sprint("Could not complete BulkWrite"), err.Error())
 		coll.tracer.AddExceptionTypeAndMessage(
 			"mongo-driver",
 			err.Error(),
@@ -137,6 +151,9 @@ func (coll *MongoCollectionWrapper) BulkWrite(
 func (coll *MongoCollectionWrapper) DeleteOne(
 	ctx context.Context, filter interface{}, opts ...*mongoOptions.DeleteOptions,
 ) (*mongo.DeleteResult, error) {
+	if coll.disabled {
+		return coll.collection.DeleteOne(ctx, filter, opts...)
+	}
 	event := startMongoEvent("DeleteOne", coll)
 	response, err := coll.collection.DeleteOne(
 		ctx,
@@ -144,7 +161,7 @@ func (coll *MongoCollectionWrapper) DeleteOne(
 		opts...,
 	)
 	if err != nil {
-		logOperationFailure(fmt.Sprint("Could not complete DeleteOne"), err.Error())
+		logOperationFailure(coll.tracer, fmt.Sprint("Could not complete DeleteOne"), err.Error())
 		coll.tracer.AddExceptionTypeAndMessage(
 			"mongo-driver",
 			err.Error(),
@@ -164,6 +181,9 @@ func (coll *MongoCollectionWrapper) DeleteOne(
 func (coll *MongoCollectionWrapper) DeleteMany(
 	ctx context.Context, filter interface{}, opts ...*mongoOptions.DeleteOptions,
 ) (*mongo.DeleteResult, error) {
+	if coll.disabled {
+		return coll.collection.DeleteMany(ctx, filter, opts...)
+	}
 	event := startMongoEvent("DeleteMany", coll)
 	response, err := coll.collection.DeleteMany(
 		ctx,
@@ -171,7 +191,7 @@ func (coll *MongoCollectionWrapper) DeleteMany(
 		opts...,
 	)
 	if err != nil {
-		logOperationFailure(fmt.Sprintf("Could not complete DeleteMany"), err.Error())
+		logOperationFailure(coll.tracer, fmt.Sprintf("Could not complete DeleteMany"), err.Error())
 		coll.tracer.AddExceptionTypeAndMessage(
 			"mongo-driver",
 			err.Error(),
@@ -191,6 +211,9 @@ func (coll *MongoCollectionWrapper) DeleteMany(
 func (coll *MongoCollectionWrapper) UpdateOne(
 	ctx context.Context, filter interface{}, update interface{}, opts ...*mongoOptions.UpdateOptions,
 ) (*mongo.UpdateResult, error) {
+	if coll.disabled {
+		return coll.collection.UpdateOne(ctx, filter, update, opts...)
+	}
 	event := startMongoEvent("UpdateOne", coll)
 	response, err := coll.collection.UpdateOne(
 		ctx,
@@ -199,7 +222,7 @@ func (coll *MongoCollectionWrapper) UpdateOne(
 		opts...,
 	)
 	if err != nil {
-		logOperationFailure(fmt.Sprintf("Could not complete %s", "UpdateOne"), err.Error())
+		logOperationFailure(coll.tracer, fmt.Sprintf("Could not complete %s", "UpdateOne"), err.Error())
 		coll.tracer.AddExceptionTypeAndMessage(
 			"mongo-driver",
 			err.Error(),
@@ -220,6 +243,9 @@ func (coll *MongoCollectionWrapper) UpdateOne(
 func (coll *MongoCollectionWrapper) UpdateMany(
 	ctx context.Context, filter interface{}, update interface{}, opts ...*mongoOptions.UpdateOptions,
 ) (*mongo.UpdateResult, error) {
+	if coll.disabled {
+		return coll.collection.UpdateMany(ctx, filter, update, opts...)
+	}
 	event := startMongoEvent("UpdateMany", coll)
 	response, err := coll.collection.UpdateMany(
 		ctx,
@@ -228,7 +254,7 @@ func (coll *MongoCollectionWrapper) UpdateMany(
 		opts...,
 	)
 	if err != nil {
-		logOperationFailure(fmt.Sprintf("Could not complete %s", "UpdateMany"), err.Error())
+		logOperationFailure(coll.tracer, fmt.Sprintf("Could not complete %s", "UpdateMany"), err.Error())
 		coll.tracer.AddExceptionTypeAndMessage(
 			"mongo-driver",
 			err.Error(),
@@ -249,6 +275,9 @@ func (coll *MongoCollectionWrapper) UpdateMany(
 func (coll *MongoCollectionWrapper) UpdateByID(
 	ctx context.Context, id interface{}, update interface{}, opts ...*mongoOptions.UpdateOptions,
 ) (*mongo.UpdateResult, error) {
+	if coll.disabled {
+		return coll.collection.UpdateByID(ctx, id, update, opts...)
+	}
 	event := startMongoEvent("UpdateByID", coll)
 	response, err := coll.collection.UpdateByID(
 		ctx,
@@ -257,7 +286,7 @@ func (coll *MongoCollectionWrapper) UpdateByID(
 		opts...,
 	)
 	if err != nil {
-		logOperationFailure(fmt.Sprintf("Could not complete %s", "UpdateByID"), err.Error())
+		logOperationFailure(coll.tracer, fmt.Sprintf("Could not complete %s", "UpdateByID"), err.Error())
 		coll.tracer.AddExceptionTypeAndMessage(
 			"mongo-driver",
 			err.Error(),
@@ -278,6 +307,9 @@ func (coll *MongoCollectionWrapper) UpdateByID(
 func (coll *MongoCollectionWrapper) ReplaceOne(
 	ctx context.Context, filter interface{}, replacement interface{}, opts ...*mongoOptions.ReplaceOptions,
 ) (*mongo.UpdateResult, error) {
+	if coll.disabled {
+		return coll.collection.ReplaceOne(ctx, filter, replacement, opts...)
+	}
 	event := startMongoEvent("ReplaceOne", coll)
 	response, err := coll.collection.ReplaceOne(
 		ctx,
@@ -286,7 +318,7 @@ func (coll *MongoCollectionWrapper) ReplaceOne(
 		opts...,
 	)
 	if err != nil {
-		logOperationFailure(fmt.Sprintf("Could not complete %s", "ReplaceOne"), err.Error())
+		logOperationFailure(coll.tracer, fmt.Sprintf("Could not complete %s", "ReplaceOne"), err.Error())
 		coll.tracer.AddExceptionTypeAndMessage(
 			"mongo-driver",
 			err.Error(),
@@ -307,6 +339,9 @@ func (coll *MongoCollectionWrapper) ReplaceOne(
 func (coll *MongoCollectionWrapper) Aggregate(
 	ctx context.Context, pipeline interface{}, opts ...*mongoOptions.AggregateOptions,
 ) (*mongo.Cursor, error) {
+	if coll.disabled {
+		return coll.collection.Aggregate(ctx, pipeline, opts...)
+	}
 	event := startMongoEvent("Aggregate", coll)
 	response, err := coll.collection.Aggregate(
 		ctx,
@@ -314,7 +349,7 @@ func (coll *MongoCollectionWrapper) Aggregate(
 		opts...,
 	)
 	if err != nil {
-		logOperationFailure(fmt.Sprintf("Could not complete %s", "Aggregate"), err.Error())
+		logOperationFailure(coll.tracer, fmt.Sprintf("Could not complete %s", "Aggregate"), err.Error())
 		coll.tracer.AddExceptionTypeAndMessage(
 			"mongo-driver",
 			err.Error(),
@@ -323,7 +358,7 @@ func (coll *MongoCollectionWrapper) Aggregate(
 
 	docs, err := readCursor(response)
 	if err != nil {
-		logOperationFailure("Could not complete readCursor", err.Error())
+		logOperationFailure(coll.tracer, "Could not complete readCursor", err.Error())
 	}
 
 	if event != nil {
@@ -339,6 +374,9 @@ func (coll *MongoCollectionWrapper) Aggregate(
 func (coll *MongoCollectionWrapper) CountDocuments(
 	ctx context.Context, filter interface{}, opts ...*mongoOptions.CountOptions,
 ) (int64, error) {
+	if coll.disabled {
+		return coll.collection.CountDocuments(ctx, filter, opts...)
+	}
 	event := startMongoEvent("CountDocuments", coll)
 	response, err := coll.collection.CountDocuments(
 		ctx,
@@ -346,7 +384,7 @@ func (coll *MongoCollectionWrapper) CountDocuments(
 		opts...,
 	)
 	if err != nil {
-		logOperationFailure(fmt.Sprintf("Could not complete %s", "CountDocuments"), err.Error())
+		logOperationFailure(coll.tracer, fmt.Sprintf("Could not complete %s", "CountDocuments"), err.Error())
 		coll.tracer.AddExceptionTypeAndMessage(
 			"mongo-driver",
 			err.Error(),
@@ -366,13 +404,16 @@ func (coll *MongoCollectionWrapper) CountDocuments(
 func (coll *MongoCollectionWrapper) EstimatedDocumentCount(
 	ctx context.Context, opts ...*mongoOptions.EstimatedDocumentCountOptions,
 ) (int64, error) {
+	if coll.disabled {
+		return coll.collection.EstimatedDocumentCount(ctx, opts...)
+	}
 	event := startMongoEvent("EstimatedDocumentCount", coll)
 	response, err := coll.collection.EstimatedDocumentCount(
 		ctx,
 		opts...,
 	)
 	if err != nil {
-		logOperationFailure(fmt.Sprintf("Could not complete %s", "EstimatedDocumentCount"), err.Error())
+		logOperationFailure(coll.tracer, fmt.Sprintf("Could not complete %s", "EstimatedDocumentCount"), err.Error())
 		coll.tracer.AddExceptionTypeAndMessage(
 			"mongo-driver",
 			err.Error(),
@@ -391,6 +432,9 @@ func (coll *MongoCollectionWrapper) EstimatedDocumentCount(
 func (coll *MongoCollectionWrapper) Distinct(
 	ctx context.Context, fieldName string, filter interface{}, opts ...*mongoOptions.DistinctOptions,
 ) ([]interface{}, error) {
+	if coll.disabled {
+		return coll.collection.Distinct(ctx, fieldName, filter, opts...)
+	}
 	event := startMongoEvent("Distinct", coll)
 	response, err := coll.collection.Distinct(
 		ctx,
@@ -399,7 +443,7 @@ func (coll *MongoCollectionWrapper) Distinct(
 		opts...,
 	)
 	if err != nil {
-		logOperationFailure(fmt.Sprintf("Could not complete %s", "Distinct"), err.Error())
+		logOperationFailure(coll.tracer, fmt.Sprintf("Could not complete %s", "Distinct"), err.Error())
 		coll.tracer.AddExceptionTypeAndMessage(
 			"mongo-driver",
 			err.Error(),
@@ -419,6 +463,9 @@ func (coll *MongoCollectionWrapper) Distinct(
 func (coll *MongoCollectionWrapper) Find(
 	ctx context.Context, filter interface{}, opts ...*mongoOptions.FindOptions,
 ) (*mongo.Cursor, error) {
+	if coll.disabled {
+		return coll.collection.Find(ctx, filter, opts...)
+	}
 	event := startMongoEvent("Find", coll)
 	response, err := coll.collection.Find(
 		ctx,
@@ -426,7 +473,7 @@ func (coll *MongoCollectionWrapper) Find(
 		opts...,
 	)
 	if err != nil {
-		logOperationFailure(fmt.Sprintf("Could not complete %s", "Find"), err.Error())
+		logOperationFailure(coll.tracer, fmt.Sprintf("Could not complete %s", "Find"), err.Error())
 		coll.tracer.AddExceptionTypeAndMessage(
 			"mongo-driver",
 			err.Error(),
@@ -435,7 +482,7 @@ func (coll *MongoCollectionWrapper) Find(
 
 	docs, err := readCursor(response)
 	if err != nil {
-		logOperationFailure("Could not complete readCursor", err.Error())
+		logOperationFailure(coll.tracer, "Could not complete readCursor", err.Error())
 	}
 
 	if event != nil {
@@ -451,6 +498,9 @@ func (coll *MongoCollectionWrapper) Find(
 func (coll *MongoCollectionWrapper) FindOne(
 	ctx context.Context, filter interface{}, opts ...*mongoOptions.FindOneOptions,
 ) *mongo.SingleResult {
+	if coll.disabled {
+		return coll.collection.FindOne(ctx, filter, opts...)
+	}
 	event := startMongoEvent("FindOne", coll)
 	response := coll.collection.FindOne(
 		ctx,
@@ -462,7 +512,7 @@ func (coll *MongoCollectionWrapper) FindOne(
 	response.Decode(&document)
 
 	if err := response.Err(); err != nil {
-		logOperationFailure("Could not complete Decode SingleResult", err.Error())
+		logOperationFailure(coll.tracer, "Could not complete Decode SingleResult", err.Error())
 		coll.tracer.AddExceptionTypeAndMessage(
 			"mongo-driver",
 			err.Error(),
@@ -482,6 +532,9 @@ func (coll *MongoCollectionWrapper) FindOne(
 func (coll *MongoCollectionWrapper) FindOneAndDelete(
 	ctx context.Context, filter interface{}, opts ...*mongoOptions.FindOneAndDeleteOptions,
 ) *mongo.SingleResult {
+	if coll.disabled {
+		return coll.collection.FindOneAndDelete(ctx, filter, opts...)
+	}
 	event := startMongoEvent("FindOneAndDelete", coll)
 	response := coll.collection.FindOneAndDelete(
 		ctx,
@@ -492,7 +545,7 @@ func (coll *MongoCollectionWrapper) FindOneAndDelete(
 	var document map[string]string
 	response.Decode(&document)
 	if err := response.Err(); err != nil {
-		logOperationFailure("Could not complete Decode SingleResult", err.Error())
+		logOperationFailure(coll.tracer, "Could not complete Decode SingleResult", err.Error())
 		coll.tracer.AddExceptionTypeAndMessage(
 			"mongo-driver",
 			err.Error(),
@@ -512,6 +565,9 @@ func (coll *MongoCollectionWrapper) FindOneAndDelete(
 func (coll *MongoCollectionWrapper) FindOneAndReplace(
 	ctx context.Context, filter interface{}, replacement interface{}, opts ...*mongoOptions.FindOneAndReplaceOptions,
 ) *mongo.SingleResult {
+	if coll.disabled {
+		return coll.collection.FindOneAndReplace(ctx, filter, replacement, opts...)
+	}
 	event := startMongoEvent("FindOneAndReplace", coll)
 	response := coll.collection.FindOneAndReplace(
 		ctx,
@@ -523,7 +579,7 @@ func (coll *MongoCollectionWrapper) FindOneAndReplace(
 	response.Decode(&document)
 
 	if err := response.Err(); err != nil {
-		logOperationFailure("Could not complete Decode SingleResult", err.Error())
+		logOperationFailure(coll.tracer, "Could not complete Decode SingleResult", err.Error())
 		coll.tracer.AddExceptionTypeAndMessage(
 			"mongo-driver",
 			err.Error(),
@@ -542,20 +598,45 @@ func (coll *MongoCollectionWrapper) FindOneAndReplace(
 
 }
 
+// findOneAndUpdateOptions converts the options FindOneAndUpdate accepts
+// to the options of the driver FindOneAndUpdate
+func findOneAndUpdateOptions(opts []*mongoOptions.FindOneAndReplaceOptions) []*mongoOptions.FindOneAndUpdateOptions {
+	updateOpts := make([]*mongoOptions.FindOneAndUpdateOptions, 0, len(opts))
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		updateOpts = append(updateOpts, &mongoOptions.FindOneAndUpdateOptions{
+			BypassDocumentValidation: opt.BypassDocumentValidation,
+			Collation:                opt.Collation,
+			MaxTime:                  opt.MaxTime,
+			Projection:               opt.Projection,
+			ReturnDocument:           opt.ReturnDocument,
+			Sort:                     opt.Sort,
+			Upsert:                   opt.Upsert,
+			Hint:                     opt.Hint,
+		})
+	}
+	return updateOpts
+}
+
 func (coll *MongoCollectionWrapper) FindOneAndUpdate(
 	ctx context.Context, filter interface{}, update interface{}, opts ...*mongoOptions.FindOneAndReplaceOptions,
 ) *mongo.SingleResult {
+	if coll.disabled {
+		return coll.collection.FindOneAndUpdate(ctx, filter, update, findOneAndUpdateOptions(opts)...)
+	}
 	event := startMongoEvent("FindOneAndUpdate", coll)
-	response := coll.collection.FindOneAndReplace(
+	response := coll.collection.FindOneAndUpdate(
 		ctx,
 		filter,
 		update,
-		opts...,
+		findOneAndUpdateOptions(opts)...,
 	)
 	var document map[string]string
 	response.Decode(&document)
 	if err := response.Err(); err != nil {
-		logOperationFailure("Could not complete Decode SingleResult", err.Error())
+		logOperationFailure(coll.tracer, "Could not complete Decode SingleResult", err.Error())
 		coll.tracer.AddExceptionTypeAndMessage(
 			"mongo-driver",
 			err.Error(),
@@ -573,12 +654,15 @@ func (coll *MongoCollectionWrapper) FindOneAndUpdate(
 }
 
 func (coll *MongoCollectionWrapper) Drop(ctx context.Context) error {
+	if coll.disabled {
+		return coll.collection.Drop(ctx)
+	}
 	event := startMongoEvent(currentFuncName(), coll)
 	err := coll.collection.Drop(
 		ctx,
 	)
 	if err != nil {
-		logOperationFailure(fmt.Sprintf("Could not complete %s", "Drop"), err.Error())
+		logOperationFailure(coll.tracer, fmt.Sprintf("Could not complete %s", "Drop"), err.Error())
 		coll.tracer.AddExceptionTypeAndMessage(
 			"mongo-driver",
 			err.Error(),
@@ -591,6 +675,9 @@ func (coll *MongoCollectionWrapper) Drop(ctx context.Context) error {
 }
 
 func (coll *MongoCollectionWrapper) Indexes() mongo.IndexView {
+	if coll.disabled {
+		return coll.collection.Indexes()
+	}
 	event := startMongoEvent("Indexes", coll)
 	indexView := coll.collection.Indexes()
 	if event != nil {
@@ -602,6 +689,9 @@ func (coll *MongoCollectionWrapper) Indexes() mongo.IndexView {
 func (coll *MongoCollectionWrapper) Watch(
 	ctx context.Context, pipeline interface{}, opts ...*mongoOptions.ChangeStreamOptions,
 ) (*mongo.ChangeStream, error) {
+	if coll.disabled {
+		return coll.collection.Watch(ctx, pipeline, opts...)
+	}
 	event := startMongoEvent("Watch", coll)
 	response, err := coll.collection.Watch(
 		ctx,
@@ -609,7 +699,7 @@ func (coll *MongoCollectionWrapper) Watch(
 		opts...,
 	)
 	if err != nil {
-		logOperationFailure(fmt.Sprintf("Could not complete %s", "Watch"), err.Error())
+		logOperationFailure(coll.tracer, fmt.Sprintf("Could not complete %s", "Watch"), err.Error())
 		coll.tracer.AddExceptionTypeAndMessage(
 			"mongo-driver",
 			err.Error(),
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
-	"log"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -14,7 +13,6 @@ import (
 	"github.com/epsagon/epsagon-go/epsagon"
 	"github.com/epsagon/epsagon-go/protocol"
 	"github.com/epsagon/epsagon-go/tracer"
-	"github.com/google/uuid"
 )
 
 const EPSAGON_TRACEID_HEADER_KEY = "epsagon-trace-id"
@@ -62,6 +60,10 @@ func Wrap(c http.Client, args ...context.Context) ClientWrapper {
 	return ClientWrapper{c, false, currentTracer}
 }
 
+func (c *ClientWrapper) integrationDisabled() bool {
+	return c.tracer == nil || c.tracer.GetConfig().IntegrationDisabled(tracer.HTTPClientIntegration)
+}
+
 func (c *ClientWrapper) getMetadataOnly() bool {
 	return c.MetadataOnly || c.tracer.GetConfig().MetadataOnly
 }
@@ -94,9 +96,12 @@ func (t *TracingTransport) RoundTrip(req *http.Request) (resp *http.Response, er
 	if tr == nil {
 		tr = epsagon.ExtractTracer(nil)
 		if tr != nil && tr.GetConfig().Debug {
-			log.Println("EPSAGON DEBUG: defaulting to global tracer in RoundTrip")
+			tr.GetConfig().GetLogger().Debugf("defaulting to global tracer in RoundTrip")
 		}
 	}
+	if tr == nil || tr.GetConfig().IntegrationDisabled(tracer.HTTPClientIntegration) {
+		return t.transport.RoundTrip(req)
+	}
 
 	called := false
 	defer func() {
@@ -105,19 +110,19 @@ func (t *TracingTransport) RoundTrip(req *http.Request) (resp *http.Response, er
 		}
 	}()
 	defer epsagon.GeneralEpsagonRecover("net.http.RoundTripper", "RoundTrip", t.tracer)
-	startTime := tracer.GetTimestamp()
+	startTime := tracer.GetTracerTimestamp(tr)
 	reqHeaders, reqBody := "", ""
 	if !t.getMetadataOnly(tr) {
 		reqHeaders, reqBody = epsagon.ExtractRequestData(req)
 	}
 	if !isBlacklistedURL(req.URL) {
-		req.Header[EPSAGON_TRACEID_HEADER_KEY] = []string{generateEpsagonTraceID()}
+		req.Header[EPSAGON_TRACEID_HEADER_KEY] = []string{generateEpsagonTraceID(tr)}
 	}
 
 	resp, err = t.transport.RoundTrip(req)
 
 	called = true
-	event := postSuperCall(startTime, req.URL.String(), req.Method, resp, err, t.getMetadataOnly(tr))
+	event := postSuperCall(tr, startTime, req.URL.String(), req.Method, resp, err, t.getMetadataOnly(tr))
 	t.addDataToEvent(reqHeaders, reqBody, req, event, tr)
 	tr.AddEvent(event)
 	return
@@ -165,18 +170,23 @@ func shouldAddHeaderByURL(rawUrl string) bool {
 	return !isBlacklistedURL(parsedURL)
 }
 
-func generateRandomUUID() string {
-	uuid, err := uuid.NewRandom()
-	if err != nil {
-		panic("failed to generate random UUID")
+func generateRandomUUID(currentTracer tracer.Tracer) string {
+	return strings.ReplaceAll(tracer.NewTracerID(currentTracer), "-", "")
+}
+
+// generateSpanID returns an ID of at most 16 characters
+func generateSpanID(currentTracer tracer.Tracer) string {
+	spanID := generateRandomUUID(currentTracer)
+	if len(spanID) > 16 {
+		spanID = spanID[:16]
 	}
-	return strings.ReplaceAll(uuid.String(), "-", "")
+	return spanID
 }
 
-func generateEpsagonTraceID() string {
-	traceID := generateRandomUUID()
-	spanID := generateRandomUUID()[:16]
-	parentSpanID := generateRandomUUID()[:16]
+func generateEpsagonTraceID(currentTracer tracer.Tracer) string {
+	traceID := generateRandomUUID(currentTracer)
+	spanID := generateSpanID(currentTracer)
+	parentSpanID := generateSpanID(currentTracer)
 	return fmt.Sprintf("%s:%s:%s:1", traceID, spanID, parentSpanID)
 }
 
@@ -225,6 +235,9 @@ func (c *ClientWrapper) addDataToEvent(req *http.Request, resp *http.Response, e
 
 // Do wraps http.Client's Do
 func (c *ClientWrapper) Do(req *http.Request) (resp *http.Response, err error) {
+	if c.integrationDisabled() {
+		return c.Client.Do(req)
+	}
 	called := false
 	defer func() {
 		if !called {
@@ -232,13 +245,13 @@ func (c *ClientWrapper) Do(req *http.Request) (resp *http.Response, err error) {
 		}
 	}()
 	defer epsagon.GeneralEpsagonRecover("net.http.Client", "Client.Do", c.tracer)
-	startTime := tracer.GetTimestamp()
+	startTime := tracer.GetTracerTimestamp(c.tracer)
 	if !isBlacklistedURL(req.URL) {
-		req.Header[EPSAGON_TRACEID_HEADER_KEY] = []string{generateEpsagonTraceID()}
+		req.Header[EPSAGON_TRACEID_HEADER_KEY] = []string{generateEpsagonTraceID(c.tracer)}
 	}
 	resp, err = c.Client.Do(req)
 	called = true
-	event := postSuperCall(startTime, req.URL.String(), req.Method, resp, err, c.getMetadataOnly())
+	event := postSuperCall(c.tracer, startTime, req.URL.String(), req.Method, resp, err, c.getMetadataOnly())
 	c.addDataToEvent(req, resp, event)
 	c.tracer.AddEvent(event)
 	return
@@ -246,6 +259,9 @@ func (c *ClientWrapper) Do(req *http.Request) (resp *http.Response, err error) {
 
 // Get wraps http.Client.Get
 func (c *ClientWrapper) Get(rawUrl string) (resp *http.Response, err error) {
+	if c.integrationDisabled() {
+		return c.Client.Get(rawUrl)
+	}
 	called := false
 	defer func() {
 		if !called {
@@ -253,17 +269,17 @@ func (c *ClientWrapper) Get(rawUrl string) (resp *http.Response, err error) {
 		}
 	}()
 	defer epsagon.GeneralEpsagonRecover("net.http.Client", "Client.Get", c.tracer)
-	startTime := tracer.GetTimestamp()
+	startTime := tracer.GetTracerTimestamp(c.tracer)
 	req, err := http.NewRequest(http.MethodGet, rawUrl, nil)
 	if err != nil || !shouldAddHeaderByURL(rawUrl) {
 		// err might be nil if rawUrl is invalid. Then, wrapping without any HTTP trace correlation
 		resp, err = c.Client.Get(rawUrl)
 	} else {
-		req.Header[EPSAGON_TRACEID_HEADER_KEY] = []string{generateEpsagonTraceID()}
+		req.Header[EPSAGON_TRACEID_HEADER_KEY] = []string{generateEpsagonTraceID(c.tracer)}
 		resp, err = c.Client.Do(req)
 	}
 	called = true
-	event := postSuperCall(startTime, rawUrl, http.MethodGet, resp, err, c.getMetadataOnly())
+	event := postSuperCall(c.tracer, startTime, rawUrl, http.MethodGet, resp, err, c.getMetadataOnly())
 	c.addDataToEvent(req, resp, event)
 	c.tracer.AddEvent(event)
 	return
@@ -272,6 +288,9 @@ func (c *ClientWrapper) Get(rawUrl string) (resp *http.Response, err error) {
 // Post wraps http.Client.Post
 func (c *ClientWrapper) Post(
 	rawUrl string, contentType string, body io.Reader) (resp *http.Response, err error) {
+	if c.integrationDisabled() {
+		return c.Client.Post(rawUrl, contentType, body)
+	}
 	called := false
 	defer func() {
 		if !called {
@@ -279,18 +298,18 @@ func (c *ClientWrapper) Post(
 		}
 	}()
 	defer epsagon.GeneralEpsagonRecover("net.http.Client", "Client.Post", c.tracer)
-	startTime := tracer.GetTimestamp()
+	startTime := tracer.GetTracerTimestamp(c.tracer)
 	req, err := http.NewRequest(http.MethodPost, rawUrl, body)
 	if err != nil || !shouldAddHeaderByURL(rawUrl) {
 		// err might be nil if rawUrl is invalid. Then, wrapping without any HTTP trace correlation
 		resp, err = c.Client.Post(rawUrl, contentType, body)
 	} else {
 		req.Header.Set("Content-Type", contentType)
-		req.Header[EPSAGON_TRACEID_HEADER_KEY] = []string{generateEpsagonTraceID()}
+		req.Header[EPSAGON_TRACEID_HEADER_KEY] = []string{generateEpsagonTraceID(c.tracer)}
 		resp, err = c.Client.Do(req)
 	}
 	called = true
-	event := postSuperCall(startTime, rawUrl, http.MethodPost, resp, err, c.getMetadataOnly())
+	event := postSuperCall(c.tracer, startTime, rawUrl, http.MethodPost, resp, err, c.getMetadataOnly())
 	c.addDataToEvent(req, resp, event)
 	c.tracer.AddEvent(event)
 	return
@@ -299,6 +318,9 @@ func (c *ClientWrapper) Post(
 // PostForm wraps http.Client.PostForm
 func (c *ClientWrapper) PostForm(
 	rawUrl string, data url.Values) (resp *http.Response, err error) {
+	if c.integrationDisabled() {
+		return c.Client.PostForm(rawUrl, data)
+	}
 	called := false
 	defer func() {
 		if !called {
@@ -306,18 +328,18 @@ func (c *ClientWrapper) PostForm(
 		}
 	}()
 	defer epsagon.GeneralEpsagonRecover("net.http.Client", "Client.PostForm", c.tracer)
-	startTime := tracer.GetTimestamp()
+	startTime := tracer.GetTracerTimestamp(c.tracer)
 	req, err := http.NewRequest(http.MethodPost, rawUrl, strings.NewReader(data.Encode()))
 	if err != nil || !shouldAddHeaderByURL(rawUrl) {
 		// err might be nil if rawUrl is invalid. Then, wrapping without any HTTP trace correlation
 		resp, err = c.Client.PostForm(rawUrl, data)
 	} else {
 		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-		req.Header[EPSAGON_TRACEID_HEADER_KEY] = []string{generateEpsagonTraceID()}
+		req.Header[EPSAGON_TRACEID_HEADER_KEY] = []string{generateEpsagonTraceID(c.tracer)}
 		resp, err = c.Client.Do(req)
 	}
 	called = true
-	event := postSuperCall(startTime, rawUrl, http.MethodPost, resp, err, c.getMetadataOnly())
+	event := postSuperCall(c.tracer, startTime, rawUrl, http.MethodPost, resp, err, c.getMetadataOnly())
 	c.addDataToEvent(req, resp, event)
 	c.tracer.AddEvent(event)
 	return
@@ -325,6 +347,9 @@ func (c *ClientWrapper) PostForm(
 
 // Head wraps http.Client.Head
 func (c *ClientWrapper) Head(rawUrl string) (resp *http.Response, err error) {
+	if c.integrationDisabled() {
+		return c.Client.Head(rawUrl)
+	}
 	called := false
 	defer func() {
 		if !called {
@@ -332,23 +357,24 @@ func (c *ClientWrapper) Head(rawUrl string) (resp *http.Response, err error) {
 		}
 	}()
 	defer epsagon.GeneralEpsagonRecover("net.http.Client", "Client.Head", c.tracer)
-	startTime := tracer.GetTimestamp()
+	startTime := tracer.GetTracerTimestamp(c.tracer)
 	req, err := http.NewRequest(http.MethodHead, rawUrl, nil)
 	if err != nil || !shouldAddHeaderByURL(rawUrl) {
 		// err might be nil if rawUrl is invalid. Then, wrapping without any HTTP trace correlation
 		resp, err = c.Client.Head(rawUrl)
 	} else {
-		req.Header[EPSAGON_TRACEID_HEADER_KEY] = []string{generateEpsagonTraceID()}
+		req.Header[EPSAGON_TRACEID_HEADER_KEY] = []string{generateEpsagonTraceID(c.tracer)}
 		resp, err = c.Client.Do(req)
 	}
 	called = true
-	event := postSuperCall(startTime, rawUrl, http.MethodHead, resp, err, c.getMetadataOnly())
+	event := postSuperCall(c.tracer, startTime, rawUrl, http.MethodHead, resp, err, c.getMetadataOnly())
 	c.addDataToEvent(req, resp, event)
 	c.tracer.AddEvent(event)
 	return
 }
 
 func postSuperCall(
+	currentTracer tracer.Tracer,
 	startTime float64,
 	url string,
 	method string,
@@ -356,10 +382,10 @@ func postSuperCall(
 	err error,
 	metadataOnly bool) *protocol.Event {
 
-	endTime := tracer.GetTimestamp()
+	endTime := tracer.GetTracerTimestamp(currentTracer)
 	duration := endTime - startTime
 
-	event := createHTTPEvent(url, method, err)
+	event := createHTTPEvent(currentTracer, url, method, err)
 	event.StartTime = startTime
 	event.Duration = duration
 	if resp != nil {
@@ -368,13 +394,13 @@ func postSuperCall(
 	return event
 }
 
-func createHTTPEvent(url, method string, err error) *protocol.Event {
+func createHTTPEvent(currentTracer tracer.Tracer, url, method string, err error) *protocol.Event {
 	errorcode := protocol.ErrorCode_OK
 	if err != nil {
 		errorcode = protocol.ErrorCode_ERROR
 	}
 	return &protocol.Event{
-		Id:        "http.Client-" + uuid.New().String(),
+		Id:        "http.Client-" + tracer.NewTracerID(currentTracer),
 		Origin:    "http.Client",
 		ErrorCode: errorcode,
 		Resource: &protocol.Resource{
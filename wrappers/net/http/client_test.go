@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/epsagon/epsagon-go/epsagon"
 	"github.com/epsagon/epsagon-go/protocol"
@@ -101,6 +102,20 @@ var _ = Describe("ClientWrapper", func() {
 				verifyTraceIDExists(events[0])
 			})
 		})
+		Context("sending a request with the integration disabled", func() {
+			It("passes the request through without an event", func() {
+				tracer.GlobalTracer.GetConfig().DisabledIntegrations = []string{tracer.HTTPClientIntegration}
+				client := Wrap(http.Client{})
+				req, err := http.NewRequest(http.MethodGet, testServer.URL, nil)
+				if err != nil {
+					Fail("couldn't create request")
+				}
+				response, err := client.Do(req)
+				verifyResponseSuccess(response, err)
+				Expect(events).To(BeEmpty())
+				Expect(requests[0].Header).NotTo(HaveKey(EPSAGON_TRACEID_HEADER_KEY))
+			})
+		})
 		Context("sending a request to existing server, no tracer", func() {
 			It("adds an event with no error", func() {
 				tracer.GlobalTracer = nil
@@ -147,6 +162,23 @@ var _ = Describe("ClientWrapper", func() {
 				verifyTraceIDNotExists(events[0])
 			})
 		})
+		Context("tracer with a clock and ID generator", func() {
+			It("Uses them for the event and trace ID", func() {
+				now := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+				tracer.GlobalTracer.GetConfig().Clock = func() time.Time { return now }
+				tracer.GlobalTracer.GetConfig().IDGenerator = func() string { return "id" }
+				client := Wrap(http.Client{})
+				req, err := http.NewRequest(http.MethodGet, testServer.URL, nil)
+				Expect(err).To(BeNil())
+				response, err := client.Do(req)
+				verifyResponseSuccess(response, err)
+				Expect(events).To(HaveLen(1))
+				Expect(events[0].Id).To(Equal("http.Client-id"))
+				Expect(events[0].StartTime).To(Equal(tracer.Timestamp(now)))
+				Expect(events[0].Duration).To(BeZero())
+				Expect(requests[0].Header.Get(EPSAGON_TRACEID_HEADER_KEY)).To(Equal("id:id:id:1"))
+			})
+		})
 	})
 	Describe(".Get", func() {
 		BeforeEach(func() {
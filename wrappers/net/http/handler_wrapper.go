@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
-	"runtime/debug"
 
 	"github.com/epsagon/epsagon-go/epsagon"
 	"github.com/epsagon/epsagon-go/protocol"
@@ -21,12 +20,8 @@ func processRawQuery(urlObj *url.URL, wrapperTracer tracer.Tracer) string {
 	}
 	processed, err := json.Marshal(urlObj.Query())
 	if err != nil {
-		wrapperTracer.AddException(&protocol.Exception{
-			Type:      "trigger-creation",
-			Message:   fmt.Sprintf("Failed to serialize query params %s", urlObj.RawQuery),
-			Traceback: string(debug.Stack()),
-			Time:      tracer.GetTimestamp(),
-		})
+		wrapperTracer.AddException(tracer.NewTracerException(wrapperTracer,
+			"trigger-creation", fmt.Sprintf("Failed to serialize query params %s", urlObj.RawQuery), err))
 		return ""
 	}
 	return string(processed)
@@ -41,7 +36,7 @@ func CreateHTTPTriggerEvent(wrapperTracer tracer.Tracer, request *http.Request,
 	event := &protocol.Event{
 		Id:        "",
 		Origin:    "trigger",
-		StartTime: tracer.GetTimestamp(),
+		StartTime: tracer.GetTracerTimestamp(wrapperTracer),
 		Resource: &protocol.Resource{
 			Name:      name,
 			Type:      "http",
@@ -76,6 +71,10 @@ func WrapHandleFunc(
 		hostName = names[1]
 	}
 	return func(rw http.ResponseWriter, request *http.Request) {
+		if config.IntegrationDisabled(tracer.HTTPServerIntegration) {
+			handler(rw, request)
+			return
+		}
 		wrapperTracer := tracer.CreateTracer(&config.Config)
 		wrapperTracer.Start()
 		defer wrapperTracer.Stop()
@@ -91,10 +90,15 @@ func WrapHandleFunc(
 			}
 		}()
 
-		newRequest := request.WithContext(
-			epsagon.ContextWithTracer(wrapperTracer, request.Context()))
+		if traceIDHeader := wrapperTracer.GetConfig().TraceIDHeader; len(traceIDHeader) > 0 {
+			rw.Header().Set(traceIDHeader, tracer.GetTracerTraceID(wrapperTracer))
+		}
+		requestID := epsagon.ServerRequestID(
+			request.Header.Get(epsagon.RequestIDHeader), wrapperTracer)
+		newRequest := request.WithContext(epsagon.ContextWithRequestID(
+			epsagon.ContextWithTracer(wrapperTracer, request.Context()), requestID))
 
-		if !config.MetadataOnly {
+		if !wrapperTracer.GetConfig().MetadataOnly {
 			rw = &WrappedResponseWriter{
 				ResponseWriter: rw,
 				resource:       triggerEvent.Resource,
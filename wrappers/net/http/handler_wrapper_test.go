@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"os"
 
 	"github.com/epsagon/epsagon-go/epsagon"
 	"github.com/epsagon/epsagon-go/protocol"
@@ -69,6 +70,62 @@ var _ = Describe("http_wrapper", func() {
 					tracer.GlobalTracer.(*tracer.MockedEpsagonTracer).Labels["test"],
 				).To(Equal("ok"))
 			})
+			It("Exposes the trace and request IDs", func() {
+				config.TraceIDHeader = "X-Epsagon-Trace-Id"
+				tracer.GlobalTracer.(*tracer.MockedEpsagonTracer).TraceID = "test-trace-id"
+				request.Header.Set(epsagon.RequestIDHeader, "test-request-id")
+				wrapper := WrapHandleFunc(
+					config,
+					func(rw http.ResponseWriter, req *http.Request) {
+						called = true
+						Expect(epsagon.TraceID(req.Context())).To(Equal("test-trace-id"))
+						Expect(epsagon.RequestID(req.Context())).To(Equal("test-request-id"))
+					},
+				)
+				wrapper(responseWriter, request)
+				Expect(called).To(Equal(true))
+				Expect(responseWriter.Header().Get("X-Epsagon-Trace-Id")).To(Equal("test-trace-id"))
+			})
+			It("Passes requests through when the integration is disabled", func() {
+				config.DisabledIntegrations = []string{tracer.HTTPServerIntegration}
+				wrapper := WrapHandleFunc(
+					config,
+					func(rw http.ResponseWriter, req *http.Request) {
+						called = true
+						Expect(epsagon.TraceID(req.Context())).To(BeEmpty())
+					},
+				)
+				wrapper(responseWriter, request)
+				Expect(called).To(Equal(true))
+				Expect(events).To(BeEmpty())
+			})
+			It("Checks whether the integration is disabled on every request", func() {
+				wrapper := WrapHandleFunc(
+					config,
+					func(rw http.ResponseWriter, req *http.Request) { called = true },
+				)
+				os.Setenv(tracer.DisabledIntegrationsEnvVar, tracer.HTTPServerIntegration)
+				defer os.Unsetenv(tracer.DisabledIntegrationsEnvVar)
+				wrapper(responseWriter, request)
+				Expect(called).To(Equal(true))
+				Expect(events).To(BeEmpty())
+
+				os.Unsetenv(tracer.DisabledIntegrationsEnvVar)
+				wrapper(responseWriter, httptest.NewRequest("GET", "https://www.help.com", nil))
+				Expect(events).NotTo(BeEmpty())
+			})
+			It("Doesn't set the trace ID response header by default", func() {
+				wrapper := WrapHandleFunc(
+					config,
+					func(rw http.ResponseWriter, req *http.Request) {
+						called = true
+						Expect(epsagon.RequestID(req.Context())).To(Equal(epsagon.TraceID(req.Context())))
+					},
+				)
+				wrapper(responseWriter, request)
+				Expect(called).To(Equal(true))
+				Expect(responseWriter.Header()).NotTo(HaveKey("X-Epsagon-Trace-Id"))
+			})
 			It("Creates a runner and trigger events for handler invocation", func() {
 				wrapper := WrapHandleFunc(
 					config,
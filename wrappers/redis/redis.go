@@ -5,13 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"net"
-	"runtime/debug"
 
 	"github.com/epsagon/epsagon-go/epsagon"
 	"github.com/epsagon/epsagon-go/protocol"
 	"github.com/epsagon/epsagon-go/tracer"
 	"github.com/go-redis/redis/v8"
-	"github.com/google/uuid"
 )
 
 type epsagonHook struct {
@@ -32,7 +30,7 @@ func wrapClient(client *redis.Client, opt *redis.Options, epsagonCtx context.Con
 	defer func() { recover() }()
 
 	currentTracer := epsagon.ExtractTracer([]context.Context{epsagonCtx})
-	if currentTracer != nil {
+	if currentTracer != nil && !currentTracer.GetConfig().IntegrationDisabled(tracer.RedisIntegration) {
 		host, port := getClientHostPort(opt)
 		client.AddHook(&epsagonHook{
 			host:    host,
@@ -111,16 +109,13 @@ func (epsHook *epsagonHook) after(response, errMsg string) {
 		event.Resource.Metadata["redis.response"] = response
 	}
 
-	eventEndTime := tracer.GetTimestamp()
+	eventEndTime := tracer.GetTracerTimestamp(epsHook.tracer)
 	event.Duration = eventEndTime - event.StartTime
 
 	if errMsg != "" {
 		event.ErrorCode = protocol.ErrorCode_EXCEPTION
-		event.Exception = &protocol.Exception{
-			Message:   errMsg,
-			Traceback: string(debug.Stack()),
-			Time:      eventEndTime,
-		}
+		event.Exception = tracer.NewTracerException(epsHook.tracer, "", errMsg, nil)
+		event.Exception.Time = eventEndTime
 	}
 
 	epsHook.tracer.AddEvent(event)
@@ -128,8 +123,8 @@ func (epsHook *epsagonHook) after(response, errMsg string) {
 
 func createEvent(epsHook *epsagonHook, operation string, metadata map[string]string) *protocol.Event {
 	return &protocol.Event{
-		Id:        "redis-" + uuid.New().String(),
-		StartTime: tracer.GetTimestamp(),
+		Id:        "redis-" + tracer.NewTracerID(epsHook.tracer),
+		StartTime: tracer.GetTracerTimestamp(epsHook.tracer),
 		Resource: &protocol.Resource{
 			Name:      epsHook.host,
 			Type:      "redis",
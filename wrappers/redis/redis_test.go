@@ -62,6 +62,14 @@ var _ = Describe("Redis wrapper", func() {
 	})
 
 	Context("Single operation", func() {
+		It("Doesn't add events when the integration is disabled", func() {
+			tracerMock.Config.DisabledIntegrations = []string{tracer.RedisIntegration}
+			client = epsagonredis.NewClient(&redis.Options{Addr: redisServer.Addr()}, ctx)
+			Expect(redisServer.Set("key", "value")).To(Succeed())
+			Expect(client.Get(ctx, "key").Val()).To(Equal("value"))
+			Expect(events).To(BeEmpty())
+		})
+
 		It("Adds event, MetadataOnly=true", func() {
 			tracerMock.Config.MetadataOnly = true
 			const (
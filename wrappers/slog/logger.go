@@ -0,0 +1,49 @@
+//go:build go1.21
+// +build go1.21
+
+package epsagonslog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/epsagon/epsagon-go/tracer"
+)
+
+// ComponentKey is the attribute added to Epsagon's internal diagnostics logged with NewLogger
+const ComponentKey = "component"
+
+type logger struct {
+	logger *slog.Logger
+}
+
+// NewLogger creates a tracer.Logger that writes Epsagon's internal
+// diagnostics to the given slog logger, or slog.Default() if nil, with an
+// "epsagon" component attribute
+// config.Logger = epsagonslog.NewLogger(slog.Default())
+func NewLogger(slogLogger *slog.Logger) tracer.Logger {
+	if slogLogger == nil {
+		slogLogger = slog.Default()
+	}
+	return &logger{logger: slogLogger.With(ComponentKey, "epsagon")}
+}
+
+func (l *logger) log(level slog.Level, format string, args ...interface{}) {
+	ctx := context.Background()
+	if l.logger.Enabled(ctx, level) {
+		l.logger.Log(ctx, level, fmt.Sprintf(format, args...))
+	}
+}
+
+// Debugf implements tracer.Logger
+func (l *logger) Debugf(format string, args ...interface{}) { l.log(slog.LevelDebug, format, args...) }
+
+// Infof implements tracer.Logger
+func (l *logger) Infof(format string, args ...interface{}) { l.log(slog.LevelInfo, format, args...) }
+
+// Warnf implements tracer.Logger
+func (l *logger) Warnf(format string, args ...interface{}) { l.log(slog.LevelWarn, format, args...) }
+
+// Errorf implements tracer.Logger
+func (l *logger) Errorf(format string, args ...interface{}) { l.log(slog.LevelError, format, args...) }
@@ -0,0 +1,70 @@
+//go:build go1.21
+// +build go1.21
+
+package epsagonslog
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/epsagon/epsagon-go/epsagon"
+	"github.com/epsagon/epsagon-go/tracer"
+)
+
+// Options configures the Epsagon slog handler
+type Options struct {
+	// CaptureLogs attaches the last log lines to the runner event of the trace
+	CaptureLogs bool
+	// CaptureLevel is the minimum level of captured log lines, defaults to slog.LevelInfo
+	CaptureLevel slog.Leveler
+}
+
+// Handler is a slog.Handler that adds the current trace ID to every log record
+// and optionally captures the log lines in the trace
+type Handler struct {
+	handler slog.Handler
+	options Options
+}
+
+// NewHandler wraps a slog.Handler with Epsagon's log correlation.
+// The tracer is taken from the context passed to the logger, or the global tracer
+func NewHandler(handler slog.Handler, options *Options) *Handler {
+	wrapped := &Handler{handler: handler}
+	if options != nil {
+		wrapped.options = *options
+	}
+	if wrapped.options.CaptureLevel == nil {
+		wrapped.options.CaptureLevel = slog.LevelInfo
+	}
+	return wrapped
+}
+
+// Enabled implements slog.Handler
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler
+func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
+	currentTracer := epsagon.TracerFromContext(ctx)
+	if currentTracer != nil {
+		record = record.Clone()
+		record.AddAttrs(slog.String(epsagon.TraceIDLogKey, tracer.GetTracerTraceID(currentTracer)))
+		if h.options.CaptureLogs && record.Level >= h.options.CaptureLevel.Level() {
+			tracer.AddTracerLog(currentTracer, tracer.NewLogRecord(
+				record.Time, strings.ToLower(record.Level.String()), record.Message))
+		}
+	}
+	return h.handler.Handle(ctx, record)
+}
+
+// WithAttrs implements slog.Handler
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &Handler{handler: h.handler.WithAttrs(attrs), options: h.options}
+}
+
+// WithGroup implements slog.Handler
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{handler: h.handler.WithGroup(name), options: h.options}
+}
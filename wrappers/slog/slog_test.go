@@ -0,0 +1,82 @@
+//go:build go1.21
+// +build go1.21
+
+package epsagonslog_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/epsagon/epsagon-go/epsagon"
+	"github.com/epsagon/epsagon-go/protocol"
+	"github.com/epsagon/epsagon-go/tracer"
+	epsagonslog "github.com/epsagon/epsagon-go/wrappers/slog"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestSlogWrapper(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Slog Wrapper")
+}
+
+var _ = Describe("Slog handler", func() {
+	var (
+		events     []*protocol.Event
+		output     *bytes.Buffer
+		ctx        context.Context
+		tracerMock *tracer.MockedEpsagonTracer
+	)
+
+	BeforeEach(func() {
+		events = make([]*protocol.Event, 0)
+		tracerMock = &tracer.MockedEpsagonTracer{
+			Events:  &events,
+			TraceID: "test-trace-id",
+			Config:  &tracer.Config{Disable: true, TestMode: true},
+		}
+		ctx = epsagon.ContextWithTracer(tracerMock)
+		output = &bytes.Buffer{}
+	})
+
+	It("Adds the trace ID to every record", func() {
+		logger := slog.New(epsagonslog.NewHandler(slog.NewJSONHandler(output, nil), nil))
+		logger.With("key", "value").InfoContext(ctx, "hello")
+		Expect(output.String()).To(ContainSubstring(`"epsagon.trace_id":"test-trace-id"`))
+		Expect(output.String()).To(ContainSubstring(`"key":"value"`))
+		Expect(tracerMock.Logs).To(BeEmpty())
+	})
+
+	It("Captures records at or above the capture level", func() {
+		logger := slog.New(epsagonslog.NewHandler(slog.NewJSONHandler(output, nil), &epsagonslog.Options{
+			CaptureLogs:  true,
+			CaptureLevel: slog.LevelWarn,
+		}))
+		logger.InfoContext(ctx, "info line")
+		logger.WarnContext(ctx, "warn line")
+		Expect(tracerMock.Logs).To(HaveLen(1))
+		Expect(tracerMock.Logs[0].Level).To(Equal("warn"))
+		Expect(tracerMock.Logs[0].Message).To(Equal("warn line"))
+	})
+
+	It("Leaves records untouched without a tracer", func() {
+		logger := slog.New(epsagonslog.NewHandler(slog.NewJSONHandler(output, nil), nil))
+		logger.Info("hello")
+		Expect(output.String()).NotTo(ContainSubstring(epsagon.TraceIDLogKey))
+	})
+})
+
+var _ = Describe("Slog logger", func() {
+	It("Writes the tracer diagnostics at their level", func() {
+		output := &bytes.Buffer{}
+		logger := epsagonslog.NewLogger(slog.New(slog.NewJSONHandler(output, nil)))
+		logger.Debugf("hidden")
+		logger.Warnf("dropping %d events", 3)
+		Expect(output.String()).NotTo(ContainSubstring("hidden"))
+		Expect(output.String()).To(ContainSubstring(`"level":"WARN"`))
+		Expect(output.String()).To(ContainSubstring(`"msg":"dropping 3 events"`))
+		Expect(output.String()).To(ContainSubstring(`"component":"epsagon"`))
+	})
+})
@@ -0,0 +1,13 @@
+package epsagonzap
+
+import (
+	"github.com/epsagon/epsagon-go/tracer"
+	"go.uber.org/zap"
+)
+
+// NewLogger creates a tracer.Logger that writes Epsagon's internal
+// diagnostics to the given zap logger, under the "epsagon" logger name
+// config.Logger = epsagonzap.NewLogger(logger)
+func NewLogger(logger *zap.Logger) tracer.Logger {
+	return logger.Named("epsagon").WithOptions(zap.AddCallerSkip(1)).Sugar()
+}
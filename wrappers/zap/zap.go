@@ -0,0 +1,131 @@
+package epsagonzap
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/epsagon/epsagon-go/epsagon"
+	"github.com/epsagon/epsagon-go/tracer"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Options configures the Epsagon zap core
+type Options struct {
+	// CaptureLogs attaches the last log lines to the runner event of the trace
+	CaptureLogs bool
+	// CaptureLevel is the minimum level of captured log lines, defaults to zapcore.InfoLevel
+	CaptureLevel zapcore.Level
+}
+
+type epsagonCore struct {
+	zapcore.Core
+	ctx     context.Context
+	options Options
+	traced  atomic.Value
+}
+
+// tracedCore is the wrapped core with the trace ID field of a trace
+type tracedCore struct {
+	traceID string
+	core    zapcore.Core
+}
+
+// captureCore captures the entries it is given to the trace
+type captureCore struct {
+	tracer tracer.Tracer
+}
+
+// NewCore wraps a zapcore.Core with Epsagon's log correlation, adding the
+// current trace ID to every log entry. The tracer is taken from the given
+// context, or the global tracer if no context is given
+func NewCore(core zapcore.Core, options *Options, ctx ...context.Context) zapcore.Core {
+	wrapped := &epsagonCore{Core: core}
+	if options != nil {
+		wrapped.options = *options
+	}
+	if len(ctx) > 0 {
+		wrapped.ctx = ctx[0]
+	}
+	return wrapped
+}
+
+// WrapCore returns a zap.Option that wraps the logger core with NewCore
+// logger = logger.WithOptions(epsagonzap.WrapCore(nil, ctx))
+func WrapCore(options *Options, ctx ...context.Context) zap.Option {
+	return zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return NewCore(core, options, ctx...)
+	})
+}
+
+// With implements zapcore.Core
+func (c *epsagonCore) With(fields []zapcore.Field) zapcore.Core {
+	return &epsagonCore{Core: c.Core.With(fields), ctx: c.ctx, options: c.options}
+}
+
+// Check implements zapcore.Core, the wrapped core checks the entry so that
+// its levels and sampling apply
+func (c *epsagonCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	currentTracer := epsagon.TracerFromContext(c.ctx)
+	if currentTracer == nil {
+		return c.Core.Check(entry, checked)
+	}
+	checked = c.tracedCore(tracer.GetTracerTraceID(currentTracer)).Check(entry, checked)
+	if c.shouldCapture(entry) {
+		checked = checked.AddCore(entry, &captureCore{tracer: currentTracer})
+	}
+	return checked
+}
+
+// tracedCore returns the wrapped core with the trace ID field, reusing
+// it while the trace ID doesn't change
+func (c *epsagonCore) tracedCore(traceID string) zapcore.Core {
+	if traced, ok := c.traced.Load().(tracedCore); ok && traced.traceID == traceID {
+		return traced.core
+	}
+	core := c.Core.With([]zapcore.Field{zap.String(epsagon.TraceIDLogKey, traceID)})
+	c.traced.Store(tracedCore{traceID: traceID, core: core})
+	return core
+}
+
+func (c *epsagonCore) shouldCapture(entry zapcore.Entry) bool {
+	return c.options.CaptureLogs && entry.Level >= c.options.CaptureLevel && c.Core.Enabled(entry.Level)
+}
+
+// Write implements zapcore.Core, for entries written without Check
+func (c *epsagonCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	currentTracer := epsagon.TracerFromContext(c.ctx)
+	if currentTracer != nil {
+		fields = append(fields[:len(fields):len(fields)], zap.String(epsagon.TraceIDLogKey, tracer.GetTracerTraceID(currentTracer)))
+		if c.shouldCapture(entry) {
+			tracer.AddTracerLog(currentTracer, tracer.NewLogRecord(entry.Time, entry.Level.String(), entry.Message))
+		}
+	}
+	return c.Core.Write(entry, fields)
+}
+
+// Enabled implements zapcore.Core, the capture level is checked by epsagonCore
+func (c *captureCore) Enabled(zapcore.Level) bool {
+	return true
+}
+
+// With implements zapcore.Core
+func (c *captureCore) With([]zapcore.Field) zapcore.Core {
+	return c
+}
+
+// Check implements zapcore.Core
+func (c *captureCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return checked.AddCore(entry, c)
+}
+
+// Write implements zapcore.Core
+func (c *captureCore) Write(entry zapcore.Entry, _ []zapcore.Field) error {
+	tracer.AddTracerLog(c.tracer, tracer.NewLogRecord(entry.Time, entry.Level.String(), entry.Message))
+	return nil
+}
+
+// Sync implements zapcore.Core
+func (c *captureCore) Sync() error {
+	return nil
+}
@@ -0,0 +1,117 @@
+package epsagonzap_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/epsagon/epsagon-go/epsagon"
+	"github.com/epsagon/epsagon-go/protocol"
+	"github.com/epsagon/epsagon-go/tracer"
+	epsagonzap "github.com/epsagon/epsagon-go/wrappers/zap"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestZapWrapper(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Zap Wrapper")
+}
+
+var _ = Describe("Zap core", func() {
+	var (
+		events     []*protocol.Event
+		observed   *observer.ObservedLogs
+		logger     *zap.Logger
+		tracerMock *tracer.MockedEpsagonTracer
+	)
+
+	BeforeEach(func() {
+		events = make([]*protocol.Event, 0)
+		tracerMock = &tracer.MockedEpsagonTracer{
+			Events:  &events,
+			TraceID: "test-trace-id",
+			Config:  &tracer.Config{Disable: true, TestMode: true},
+		}
+		var core zapcore.Core
+		core, observed = observer.New(zapcore.DebugLevel)
+		logger = zap.New(core)
+	})
+
+	It("Adds the trace ID to every entry", func() {
+		ctx := epsagon.ContextWithTracer(tracerMock)
+		logger = logger.WithOptions(epsagonzap.WrapCore(nil, ctx))
+		logger.With(zap.String("key", "value")).Info("hello")
+		entries := observed.All()
+		Expect(entries).To(HaveLen(1))
+		Expect(entries[0].ContextMap()).To(HaveKeyWithValue(epsagon.TraceIDLogKey, "test-trace-id"))
+		Expect(entries[0].ContextMap()).To(HaveKeyWithValue("key", "value"))
+		Expect(tracerMock.Logs).To(BeEmpty())
+	})
+
+	It("Captures entries at or above the capture level", func() {
+		ctx := epsagon.ContextWithTracer(tracerMock)
+		logger = logger.WithOptions(epsagonzap.WrapCore(&epsagonzap.Options{
+			CaptureLogs:  true,
+			CaptureLevel: zapcore.WarnLevel,
+		}, ctx))
+		logger.Info("info line")
+		logger.Error("error line")
+		Expect(tracerMock.Logs).To(HaveLen(1))
+		Expect(tracerMock.Logs[0].Level).To(Equal("error"))
+		Expect(tracerMock.Logs[0].Message).To(Equal("error line"))
+	})
+
+	It("Lets the wrapped core check the entry levels", func() {
+		infoCore, infoLogs := observer.New(zapcore.InfoLevel)
+		errorCore, errorLogs := observer.New(zapcore.ErrorLevel)
+		ctx := epsagon.ContextWithTracer(tracerMock)
+		logger = zap.New(epsagonzap.NewCore(zapcore.NewTee(infoCore, errorCore), &epsagonzap.Options{
+			CaptureLogs: true,
+		}, ctx))
+		logger.Debug("debug line")
+		logger.Info("info line")
+		logger.Error("error line")
+		Expect(infoLogs.All()).To(HaveLen(2))
+		Expect(errorLogs.All()).To(HaveLen(1))
+		Expect(errorLogs.All()[0].Message).To(Equal("error line"))
+		Expect(errorLogs.All()[0].ContextMap()).To(HaveKeyWithValue(epsagon.TraceIDLogKey, "test-trace-id"))
+		Expect(tracerMock.Logs).To(HaveLen(2))
+	})
+
+	It("Lets the wrapped core sample the entries", func() {
+		core, sampledLogs := observer.New(zapcore.InfoLevel)
+		sampler := zapcore.NewSamplerWithOptions(core, time.Minute, 1, 100)
+		ctx := epsagon.ContextWithTracer(tracerMock)
+		logger = zap.New(epsagonzap.NewCore(sampler, nil, ctx))
+		for i := 0; i < 3; i++ {
+			logger.Info("repeated line")
+		}
+		Expect(sampledLogs.All()).To(HaveLen(1))
+		Expect(sampledLogs.All()[0].ContextMap()).To(HaveKeyWithValue(epsagon.TraceIDLogKey, "test-trace-id"))
+	})
+
+	It("Leaves entries untouched without a tracer", func() {
+		logger = logger.WithOptions(epsagonzap.WrapCore(nil))
+		logger.Info("hello")
+		entries := observed.All()
+		Expect(entries).To(HaveLen(1))
+		Expect(entries[0].ContextMap()).NotTo(HaveKey(epsagon.TraceIDLogKey))
+	})
+})
+
+var _ = Describe("Zap logger", func() {
+	It("Writes the tracer diagnostics at their level", func() {
+		core, observed := observer.New(zapcore.InfoLevel)
+		logger := epsagonzap.NewLogger(zap.New(core))
+		logger.Debugf("hidden")
+		logger.Warnf("dropping %d events", 3)
+		entries := observed.All()
+		Expect(entries).To(HaveLen(1))
+		Expect(entries[0].Message).To(Equal("dropping 3 events"))
+		Expect(entries[0].Level).To(Equal(zapcore.WarnLevel))
+		Expect(entries[0].LoggerName).To(Equal("epsagon"))
+	})
+})